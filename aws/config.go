@@ -22,6 +22,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/ec2metadata"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cognitoidentity"
     "github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/aws/aws-sdk-go/service/organizations"
@@ -42,6 +43,7 @@ type Config struct {
 }
 
 type AWSClient struct {
+	cognitoconn *cognitoidentity.CognitoIdentity
 	cidpconn *cognitoidentityprovider.CognitoIdentityProvider
 	orgconn *organizations.Organizations
 	iamconn *iam.IAM
@@ -121,6 +123,9 @@ func (c *Config) Client() (interface{}, error) {
 		// http://docs.aws.amazon.com/general/latest/gr/sigv4_changes.html
 		// usEast1Sess := sess.Copy(&aws.Config{Region: aws.String("us-east-1")})
 
+		log.Println("[INFO] Initializing Cognito Identity Connection")
+		client.cognitoconn = cognitoidentity.New(sess)
+
 		log.Println("[INFO] Initializing Cognito Identity Provider Connection")
 		client.cidpconn = cognitoidentityprovider.New(sess)
 
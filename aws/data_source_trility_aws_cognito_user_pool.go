@@ -0,0 +1,127 @@
+package aws
+
+import (
+    "fmt"
+
+    "github.com/hashicorp/terraform/helper/schema"
+
+    "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
+)
+
+func dataSourceTrilityAwsCognitoUserPool() *schema.Resource {
+    // The configuration attributes mirror the trility_aws_cognitoidentityprovider_user_pool
+    // resource, so that both stay in step as the resource grows
+    s := dataSourceSchemaFromResourceSchema(resourceTrilityAwsCognitoIDPUserPool().Schema)
+
+    // policies and force_destroy are not read back from the API, and the
+    // pool name becomes the lookup argument
+    delete(s, "policies")
+    delete(s, "force_destroy")
+    delete(s, "poolname")
+
+    s["name"] = &schema.Schema{
+        Type: schema.TypeString,
+        Required: true,
+    }
+    s["arn"] = &schema.Schema{
+        Type: schema.TypeString,
+        Computed: true,
+    }
+    // Base of the issuer URL in the tokens the pool hands out
+    s["endpoint"] = &schema.Schema{
+        Type: schema.TypeString,
+        Computed: true,
+    }
+    s["domain"] = &schema.Schema{
+        Type: schema.TypeString,
+        Computed: true,
+    }
+    s["custom_domain"] = &schema.Schema{
+        Type: schema.TypeString,
+        Computed: true,
+    }
+
+    return &schema.Resource{
+        Read: dataSourceCognitoIDPUserPoolRead,
+        Schema: s,
+    }
+}
+
+func dataSourceCognitoIDPUserPoolRead(d *schema.ResourceData, meta interface{}) error {
+    client := meta.(*AWSClient)
+    cidpconn := client.cidpconn
+    name := d.Get("name").(string)
+
+    params := &cognitoidentityprovider.ListUserPoolsInput{
+        MaxResults: aws.Int64(60),
+    }
+
+    ids := make([]string, 0)
+    err := cidpconn.ListUserPoolsPages(params, func(page *cognitoidentityprovider.ListUserPoolsOutput, lastPage bool) bool {
+        for _, p := range page.UserPools {
+            if aws.StringValue(p.Name) == name {
+                ids = append(ids, aws.StringValue(p.Id))
+            }
+        }
+        return !lastPage
+    })
+    if err != nil {
+        return fmt.Errorf("Error listing User Pools: %s", err)
+    }
+
+    if len(ids) == 0 {
+        return fmt.Errorf("No User Pool named %s found", name)
+    }
+    if len(ids) > 1 {
+        return fmt.Errorf("Found %d User Pools named %s (%v), names have to be unique for this lookup", len(ids), name, ids)
+    }
+
+    id := ids[0]
+    resp, err := cidpconn.DescribeUserPool(&cognitoidentityprovider.DescribeUserPoolInput{
+        UserPoolId: aws.String(id),
+    })
+    if err != nil {
+        return fmt.Errorf("Error reading User Pool %s: %s", id, err)
+    }
+
+    pool := resp.UserPool
+    d.SetId(id)
+    d.Set("arn", pool.Arn)
+    d.Set("endpoint", fmt.Sprintf("cognito-idp.%s.amazonaws.com/%s", client.region, id))
+    d.Set("domain", pool.Domain)
+    d.Set("custom_domain", pool.CustomDomain)
+
+    return setCognitoIDPUserPoolConfiguration(d, pool)
+}
+
+// dataSourceSchemaFromResourceSchema turns a resource schema into a read-only
+// one where every attribute, nested ones included, is computed
+func dataSourceSchemaFromResourceSchema(rs map[string]*schema.Schema) map[string]*schema.Schema {
+    ds := make(map[string]*schema.Schema, len(rs))
+    for k, v := range rs {
+        ds[k] = dataSourceSchemaFromResourceSchemaAttribute(v)
+    }
+    return ds
+}
+
+func dataSourceSchemaFromResourceSchemaAttribute(v *schema.Schema) *schema.Schema {
+    s := &schema.Schema{
+        Type: v.Type,
+        Computed: true,
+        Set: v.Set,
+        Sensitive: v.Sensitive,
+        Description: v.Description,
+    }
+
+    switch elem := v.Elem.(type) {
+    case *schema.Resource:
+        s.Elem = &schema.Resource{
+            Schema: dataSourceSchemaFromResourceSchema(elem.Schema),
+        }
+    case *schema.Schema:
+        s.Elem = &schema.Schema{Type: elem.Type}
+    }
+
+    return s
+}
@@ -0,0 +1,168 @@
+package aws
+
+import (
+    "encoding/json"
+    "fmt"
+    "io/ioutil"
+    "strings"
+
+    "github.com/hashicorp/go-cleanhttp"
+    "github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceTrilityAwsCognitoUserPoolSigningKeys() *schema.Resource {
+    return &schema.Resource{
+        Read: dataSourceCognitoIDPUserPoolSigningKeysRead,
+
+        Schema: map[string]*schema.Schema{
+            "user_pool_id": &schema.Schema{
+                Type: schema.TypeString,
+                Required: true,
+            },
+            // Defaults to https://cognito-idp.<region>.amazonaws.com
+            "base_url": &schema.Schema{
+                Type: schema.TypeString,
+                Optional: true,
+            },
+            "issuer": &schema.Schema{
+                Type: schema.TypeString,
+                Computed: true,
+            },
+            "jwks_uri": &schema.Schema{
+                Type: schema.TypeString,
+                Computed: true,
+            },
+            "keys": &schema.Schema{
+                Type: schema.TypeList,
+                Computed: true,
+                Elem: &schema.Resource{
+                    Schema: map[string]*schema.Schema{
+                        "kid": {
+                            Type: schema.TypeString,
+                            Computed: true,
+                        },
+                        "kty": {
+                            Type: schema.TypeString,
+                            Computed: true,
+                        },
+                        "alg": {
+                            Type: schema.TypeString,
+                            Computed: true,
+                        },
+                        "use": {
+                            Type: schema.TypeString,
+                            Computed: true,
+                        },
+                        "n": {
+                            Type: schema.TypeString,
+                            Computed: true,
+                        },
+                        "e": {
+                            Type: schema.TypeString,
+                            Computed: true,
+                        },
+                    },
+                },
+            },
+        },
+    }
+}
+
+type cognitoIDPOpenIDConfiguration struct {
+    Issuer string `json:"issuer"`
+    JwksURI string `json:"jwks_uri"`
+}
+
+type cognitoIDPJSONWebKeySet struct {
+    Keys []struct {
+        Kid string `json:"kid"`
+        Kty string `json:"kty"`
+        Alg string `json:"alg"`
+        Use string `json:"use"`
+        N string `json:"n"`
+        E string `json:"e"`
+    } `json:"keys"`
+}
+
+func dataSourceCognitoIDPUserPoolSigningKeysRead(d *schema.ResourceData, meta interface{}) error {
+    userPoolId := d.Get("user_pool_id").(string)
+
+    baseURL := fmt.Sprintf("https://cognito-idp.%s.amazonaws.com", meta.(*AWSClient).region)
+    if v, ok := d.GetOk("base_url"); ok {
+        baseURL = v.(string)
+    }
+
+    issuer, jwksURI, keys, err := fetchCognitoIDPSigningKeys(baseURL, userPoolId)
+    if err != nil {
+        return err
+    }
+
+    d.SetId(userPoolId)
+    d.Set("issuer", issuer)
+    d.Set("jwks_uri", jwksURI)
+    if err := d.Set("keys", keys); err != nil {
+        return fmt.Errorf("Error setting keys for User Pool %s: %s", userPoolId, err)
+    }
+
+    return nil
+}
+
+// fetchCognitoIDPSigningKeys follows the pool's OpenID discovery document to
+// its key set and returns the issuer, the JWKS URI and the keys
+func fetchCognitoIDPSigningKeys(baseURL, userPoolId string) (string, string, []map[string]interface{}, error) {
+    issuer := fmt.Sprintf("%s/%s", strings.TrimRight(baseURL, "/"), userPoolId)
+
+    var config cognitoIDPOpenIDConfiguration
+    if err := getCognitoIDPJSON(issuer+"/.well-known/openid-configuration", &config); err != nil {
+        return "", "", nil, fmt.Errorf("Error fetching OpenID configuration of User Pool %s: %s", userPoolId, err)
+    }
+
+    if config.Issuer != issuer {
+        return "", "", nil, fmt.Errorf("OpenID configuration of User Pool %s names issuer %q, expected %q", userPoolId, config.Issuer, issuer)
+    }
+    if config.JwksURI == "" {
+        return "", "", nil, fmt.Errorf("OpenID configuration of User Pool %s has no jwks_uri", userPoolId)
+    }
+
+    var jwks cognitoIDPJSONWebKeySet
+    if err := getCognitoIDPJSON(config.JwksURI, &jwks); err != nil {
+        return "", "", nil, fmt.Errorf("Error fetching signing keys of User Pool %s: %s", userPoolId, err)
+    }
+
+    keys := make([]map[string]interface{}, 0, len(jwks.Keys))
+    for _, k := range jwks.Keys {
+        keys = append(keys, map[string]interface{}{
+            "kid": k.Kid,
+            "kty": k.Kty,
+            "alg": k.Alg,
+            "use": k.Use,
+            "n": k.N,
+            "e": k.E,
+        })
+    }
+
+    return issuer, config.JwksURI, keys, nil
+}
+
+func getCognitoIDPJSON(url string, v interface{}) error {
+    resp, err := cleanhttp.DefaultClient().Get(url)
+    if err != nil {
+        return err
+    }
+    defer resp.Body.Close()
+
+    body, err := ioutil.ReadAll(resp.Body)
+    if err != nil {
+        return err
+    }
+
+    if resp.StatusCode != 200 {
+        return fmt.Errorf("GET %s returned %s: %s", url, resp.Status, body)
+    }
+
+    if err := json.Unmarshal(body, v); err != nil {
+        return fmt.Errorf("could not decode response from %s: %s", url, err)
+    }
+
+    return nil
+}
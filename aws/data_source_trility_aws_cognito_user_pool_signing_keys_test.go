@@ -0,0 +1,115 @@
+package aws
+
+import (
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+)
+
+const testCognitoIDPUserPoolId = "us-east-1_Example"
+
+const testCognitoIDPJWKS = `{
+  "keys": [
+    {"alg": "RS256", "e": "AQAB", "kid": "key-1", "kty": "RSA", "n": "n1", "use": "sig"},
+    {"alg": "RS256", "e": "AQAB", "kid": "key-2", "kty": "RSA", "n": "n2", "use": "sig"}
+  ]
+}`
+
+// testCognitoIDPDiscoveryServer stands in for cognito-idp. configure gets the
+// server URL and returns the discovery document to serve.
+func testCognitoIDPDiscoveryServer(t *testing.T, configure func(url string) map[string]interface{}) *httptest.Server {
+    var server *httptest.Server
+    server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch r.URL.Path {
+        case "/" + testCognitoIDPUserPoolId + "/.well-known/openid-configuration":
+            json.NewEncoder(w).Encode(configure(server.URL))
+        case "/" + testCognitoIDPUserPoolId + "/.well-known/jwks.json":
+            w.Write([]byte(testCognitoIDPJWKS))
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    return server
+}
+
+func TestFetchCognitoIDPSigningKeys(t *testing.T) {
+    server := testCognitoIDPDiscoveryServer(t, func(url string) map[string]interface{} {
+        return map[string]interface{}{
+            "issuer": url + "/" + testCognitoIDPUserPoolId,
+            "jwks_uri": url + "/" + testCognitoIDPUserPoolId + "/.well-known/jwks.json",
+        }
+    })
+    defer server.Close()
+
+    // A trailing slash on the base URL is tolerated
+    issuer, jwksURI, keys, err := fetchCognitoIDPSigningKeys(server.URL+"/", testCognitoIDPUserPoolId)
+    if err != nil {
+        t.Fatalf("unexpected error: %s", err)
+    }
+
+    if expected := server.URL + "/" + testCognitoIDPUserPoolId; issuer != expected {
+        t.Errorf("expected issuer %s, got %s", expected, issuer)
+    }
+    if expected := server.URL + "/" + testCognitoIDPUserPoolId + "/.well-known/jwks.json"; jwksURI != expected {
+        t.Errorf("expected jwks_uri %s, got %s", expected, jwksURI)
+    }
+
+    if len(keys) != 2 {
+        t.Fatalf("expected 2 keys, got %d", len(keys))
+    }
+    expected := map[string]interface{}{
+        "kid": "key-2",
+        "kty": "RSA",
+        "alg": "RS256",
+        "use": "sig",
+        "n": "n2",
+        "e": "AQAB",
+    }
+    for k, v := range expected {
+        if keys[1][k] != v {
+            t.Errorf("expected %s of the second key to be %v, got %v", k, v, keys[1][k])
+        }
+    }
+}
+
+func TestFetchCognitoIDPSigningKeys_issuerMismatch(t *testing.T) {
+    server := testCognitoIDPDiscoveryServer(t, func(url string) map[string]interface{} {
+        return map[string]interface{}{
+            "issuer": "https://cognito-idp.us-east-1.amazonaws.com/" + testCognitoIDPUserPoolId,
+            "jwks_uri": url + "/" + testCognitoIDPUserPoolId + "/.well-known/jwks.json",
+        }
+    })
+    defer server.Close()
+
+    _, _, _, err := fetchCognitoIDPSigningKeys(server.URL, testCognitoIDPUserPoolId)
+    if err == nil || !strings.Contains(err.Error(), "names issuer") {
+        t.Fatalf("expected an issuer mismatch error, got: %v", err)
+    }
+}
+
+func TestFetchCognitoIDPSigningKeys_missingJwksURI(t *testing.T) {
+    server := testCognitoIDPDiscoveryServer(t, func(url string) map[string]interface{} {
+        return map[string]interface{}{
+            "issuer": url + "/" + testCognitoIDPUserPoolId,
+        }
+    })
+    defer server.Close()
+
+    _, _, _, err := fetchCognitoIDPSigningKeys(server.URL, testCognitoIDPUserPoolId)
+    if err == nil || !strings.Contains(err.Error(), "has no jwks_uri") {
+        t.Fatalf("expected a missing jwks_uri error, got: %v", err)
+    }
+}
+
+func TestFetchCognitoIDPSigningKeys_errorResponse(t *testing.T) {
+    server := testCognitoIDPDiscoveryServer(t, nil)
+    defer server.Close()
+
+    // The discovery document of an unknown pool is a 404
+    _, _, _, err := fetchCognitoIDPSigningKeys(server.URL, "us-east-1_Unknown")
+    if err == nil || !strings.Contains(err.Error(), "404") {
+        t.Fatalf("expected an error for the 404 response, got: %v", err)
+    }
+}
@@ -0,0 +1,23 @@
+package aws
+
+import (
+    "fmt"
+    "strings"
+
+    "github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceTrilityAwsCognitoUserPoolClientImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+    parts := strings.Split(d.Id(), "/")
+    if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+        return nil, fmt.Errorf("Wrong format of import ID (%s), use: 'user-pool-id/client-id'", d.Id())
+    }
+
+    results := make([]*schema.ResourceData, 1)
+
+    d.Set("user_pool_id", parts[0])
+    d.SetId(parts[1])
+
+    results[0] = d
+    return results, nil
+}
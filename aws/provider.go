@@ -68,7 +68,27 @@ func Provider() terraform.ResourceProvider {
             },
         },
 
+        DataSourcesMap: map[string]*schema.Resource{
+            "trility_aws_cognito_user_pool": dataSourceTrilityAwsCognitoUserPool(),
+            "trility_aws_cognito_user_pool_signing_keys": dataSourceTrilityAwsCognitoUserPoolSigningKeys(),
+        },
+
         ResourcesMap: map[string]*schema.Resource{
+            "trility_aws_cognito_identity_pool": resourceTrilityAwsCognitoIdentityPool(),
+            "trility_aws_cognito_identity_pool_principal_tags": resourceTrilityAwsCognitoIdentityPoolPrincipalTags(),
+            "trility_aws_cognito_identity_pool_roles_attachment": resourceTrilityAwsCognitoIdentityPoolRolesAttachment(),
+            "trility_aws_cognito_identity_provider": resourceTrilityAwsCognitoIdentityProvider(),
+            "trility_aws_cognito_managed_login_branding": resourceTrilityAwsCognitoManagedLoginBranding(),
+            "trility_aws_cognito_resource_server": resourceTrilityAwsCognitoResourceServer(),
+            "trility_aws_cognito_risk_configuration": resourceTrilityAwsCognitoRiskConfiguration(),
+            "trility_aws_cognito_user": resourceTrilityAwsCognitoUser(),
+            "trility_aws_cognito_user_group": resourceTrilityAwsCognitoUserGroup(),
+            "trility_aws_cognito_user_group_membership": resourceTrilityAwsCognitoUserGroupMembership(),
+            "trility_aws_cognito_user_import_job": resourceTrilityAwsCognitoUserImportJob(),
+            "trility_aws_cognito_user_pool_client": resourceTrilityAwsCognitoUserPoolClient(),
+            "trility_aws_cognito_user_pool_client_secret": resourceTrilityAwsCognitoUserPoolClientSecret(),
+            "trility_aws_cognito_user_pool_domain": resourceTrilityAwsCognitoUserPoolDomain(),
+            "trility_aws_cognito_user_pool_ui_customization": resourceTrilityAwsCognitoUserPoolUICustomization(),
             "trility_aws_cognitoidentityprovider_user_pool": resourceTrilityAwsCognitoIDPUserPool(),
             "trility_aws_organizations_account": resourceTrilityAwsOrganizationsAccount(),
         },
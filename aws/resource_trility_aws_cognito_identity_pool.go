@@ -0,0 +1,260 @@
+package aws
+
+import (
+    "bytes"
+    "fmt"
+    "log"
+    "regexp"
+
+    "github.com/hashicorp/terraform/helper/hashcode"
+    "github.com/hashicorp/terraform/helper/schema"
+
+    "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cognitoidentity"
+)
+
+func resourceTrilityAwsCognitoIdentityPool() *schema.Resource {
+    return &schema.Resource{
+        Create: resourceCognitoIdentityPoolCreate,
+        Read: resourceCognitoIdentityPoolRead,
+        Update: resourceCognitoIdentityPoolUpdate,
+        Delete: resourceCognitoIdentityPoolDelete,
+        Importer: &schema.ResourceImporter{
+            State: schema.ImportStatePassthrough,
+        },
+
+        Schema: map[string]*schema.Schema{
+            "identity_pool_name": &schema.Schema{
+                Type: schema.TypeString,
+                Required: true,
+                ValidateFunc: validateCognitoIdentityPoolName,
+            },
+            "allow_unauthenticated_identities": &schema.Schema{
+                Type: schema.TypeBool,
+                Optional: true,
+                Default: false,
+            },
+            // The classic (basic) flow lets clients call GetOpenIdToken and
+            // AssumeRoleWithWebIdentity themselves
+            "allow_classic_flow": &schema.Schema{
+                Type: schema.TypeBool,
+                Optional: true,
+                Default: false,
+            },
+            // Cognito does not allow changing the developer provider once set
+            "developer_provider_name": &schema.Schema{
+                Type: schema.TypeString,
+                Optional: true,
+                ForceNew: true,
+            },
+            "cognito_identity_providers": &schema.Schema{
+                Type: schema.TypeSet,
+                Optional: true,
+                Set: cognitoIdentityProvidersHash,
+                Elem: &schema.Resource{
+                    Schema: map[string]*schema.Schema{
+                        "client_id": {
+                            Type: schema.TypeString,
+                            Required: true,
+                        },
+                        // cognito-idp.<region>.amazonaws.com/<user pool id>
+                        "provider_name": {
+                            Type: schema.TypeString,
+                            Required: true,
+                        },
+                        "server_side_token_check": {
+                            Type: schema.TypeBool,
+                            Optional: true,
+                            Default: false,
+                        },
+                    },
+                },
+            },
+            "openid_connect_provider_arns": &schema.Schema{
+                Type: schema.TypeSet,
+                Optional: true,
+                Elem: &schema.Schema{Type: schema.TypeString},
+            },
+            "saml_provider_arns": &schema.Schema{
+                Type: schema.TypeSet,
+                Optional: true,
+                Elem: &schema.Schema{Type: schema.TypeString},
+            },
+            // Public providers such as graph.facebook.com or accounts.google.com
+            // mapped to the app ID
+            "supported_login_providers": &schema.Schema{
+                Type: schema.TypeMap,
+                Optional: true,
+                Elem: &schema.Schema{Type: schema.TypeString},
+            },
+            "tags": &schema.Schema{
+                Type: schema.TypeMap,
+                Optional: true,
+                Elem: &schema.Schema{Type: schema.TypeString},
+            },
+        },
+    }
+}
+
+func resourceCognitoIdentityPoolCreate(d *schema.ResourceData, meta interface{}) error {
+    cognitoconn := meta.(*AWSClient).cognitoconn
+    name := d.Get("identity_pool_name").(string)
+
+    params := &cognitoidentity.CreateIdentityPoolInput{
+        IdentityPoolName: aws.String(name),
+        AllowUnauthenticatedIdentities: aws.Bool(d.Get("allow_unauthenticated_identities").(bool)),
+        AllowClassicFlow: aws.Bool(d.Get("allow_classic_flow").(bool)),
+        CognitoIdentityProviders: expandCognitoIdentityProviders(d.Get("cognito_identity_providers").(*schema.Set)),
+        OpenIdConnectProviderARNs: expandStringSet(d.Get("openid_connect_provider_arns").(*schema.Set)),
+        SamlProviderARNs: expandStringSet(d.Get("saml_provider_arns").(*schema.Set)),
+    }
+
+    if v, ok := d.GetOk("developer_provider_name"); ok {
+        params.DeveloperProviderName = aws.String(v.(string))
+    }
+
+    if v, ok := d.GetOk("supported_login_providers"); ok {
+        params.SupportedLoginProviders = stringMapToPointers(v.(map[string]interface{}))
+    }
+
+    if v, ok := d.GetOk("tags"); ok {
+        params.IdentityPoolTags = stringMapToPointers(v.(map[string]interface{}))
+    }
+
+    resp, err := cognitoconn.CreateIdentityPool(params)
+    if err != nil {
+        return fmt.Errorf("Error creating Identity Pool %s: %s", name, err)
+    }
+
+    d.SetId(*resp.IdentityPoolId)
+    return resourceCognitoIdentityPoolRead(d, meta)
+}
+
+func resourceCognitoIdentityPoolRead(d *schema.ResourceData, meta interface{}) error {
+    cognitoconn := meta.(*AWSClient).cognitoconn
+    id := d.Id()
+
+    resp, err := cognitoconn.DescribeIdentityPool(&cognitoidentity.DescribeIdentityPoolInput{
+        IdentityPoolId: aws.String(id),
+    })
+    if err != nil {
+        if isAWSErr(err, cognitoidentity.ErrCodeResourceNotFoundException, "") {
+            log.Printf("[WARN] Identity Pool %s not found, removing from state", id)
+            d.SetId("")
+            return nil
+        }
+        return fmt.Errorf("Error reading Identity Pool %s: %s", id, err)
+    }
+
+    d.Set("identity_pool_name", resp.IdentityPoolName)
+    d.Set("allow_unauthenticated_identities", resp.AllowUnauthenticatedIdentities)
+    d.Set("allow_classic_flow", resp.AllowClassicFlow)
+    d.Set("developer_provider_name", resp.DeveloperProviderName)
+
+    if err := d.Set("cognito_identity_providers", flattenCognitoIdentityProviders(resp.CognitoIdentityProviders)); err != nil {
+        return fmt.Errorf("Error setting cognito_identity_providers for Identity Pool %s: %s", id, err)
+    }
+
+    if err := d.Set("openid_connect_provider_arns", flattenStringSet(resp.OpenIdConnectProviderARNs)); err != nil {
+        return fmt.Errorf("Error setting openid_connect_provider_arns for Identity Pool %s: %s", id, err)
+    }
+
+    if err := d.Set("saml_provider_arns", flattenStringSet(resp.SamlProviderARNs)); err != nil {
+        return fmt.Errorf("Error setting saml_provider_arns for Identity Pool %s: %s", id, err)
+    }
+
+    if err := d.Set("supported_login_providers", pointersMapToStringList(resp.SupportedLoginProviders)); err != nil {
+        return fmt.Errorf("Error setting supported_login_providers for Identity Pool %s: %s", id, err)
+    }
+
+    if err := d.Set("tags", pointersMapToStringList(resp.IdentityPoolTags)); err != nil {
+        return fmt.Errorf("Error setting tags for Identity Pool %s: %s", id, err)
+    }
+
+    return nil
+}
+
+func resourceCognitoIdentityPoolUpdate(d *schema.ResourceData, meta interface{}) error {
+    cognitoconn := meta.(*AWSClient).cognitoconn
+    id := d.Id()
+
+    // UpdateIdentityPool replaces the whole pool, so every value is sent
+    params := &cognitoidentity.IdentityPool{
+        IdentityPoolId: aws.String(id),
+        IdentityPoolName: aws.String(d.Get("identity_pool_name").(string)),
+        AllowUnauthenticatedIdentities: aws.Bool(d.Get("allow_unauthenticated_identities").(bool)),
+        AllowClassicFlow: aws.Bool(d.Get("allow_classic_flow").(bool)),
+        CognitoIdentityProviders: expandCognitoIdentityProviders(d.Get("cognito_identity_providers").(*schema.Set)),
+        OpenIdConnectProviderARNs: expandStringSet(d.Get("openid_connect_provider_arns").(*schema.Set)),
+        SamlProviderARNs: expandStringSet(d.Get("saml_provider_arns").(*schema.Set)),
+        SupportedLoginProviders: stringMapToPointers(d.Get("supported_login_providers").(map[string]interface{})),
+        IdentityPoolTags: stringMapToPointers(d.Get("tags").(map[string]interface{})),
+    }
+
+    if v, ok := d.GetOk("developer_provider_name"); ok {
+        params.DeveloperProviderName = aws.String(v.(string))
+    }
+
+    _, err := cognitoconn.UpdateIdentityPool(params)
+    if err != nil {
+        return fmt.Errorf("Error updating Identity Pool %s: %s", id, err)
+    }
+
+    return resourceCognitoIdentityPoolRead(d, meta)
+}
+
+func resourceCognitoIdentityPoolDelete(d *schema.ResourceData, meta interface{}) error {
+    cognitoconn := meta.(*AWSClient).cognitoconn
+    id := d.Id()
+
+    _, err := cognitoconn.DeleteIdentityPool(&cognitoidentity.DeleteIdentityPoolInput{
+        IdentityPoolId: aws.String(id),
+    })
+    if err != nil && !isAWSErr(err, cognitoidentity.ErrCodeResourceNotFoundException, "") {
+        return fmt.Errorf("Error removing Identity Pool %s: %s", id, err)
+    }
+
+    return nil
+}
+
+func expandCognitoIdentityProviders(s *schema.Set) []*cognitoidentity.Provider {
+    providers := make([]*cognitoidentity.Provider, 0, s.Len())
+    for _, raw := range s.List() {
+        m := raw.(map[string]interface{})
+        providers = append(providers, &cognitoidentity.Provider{
+            ClientId: aws.String(m["client_id"].(string)),
+            ProviderName: aws.String(m["provider_name"].(string)),
+            ServerSideTokenCheck: aws.Bool(m["server_side_token_check"].(bool)),
+        })
+    }
+    return providers
+}
+
+func flattenCognitoIdentityProviders(providers []*cognitoidentity.Provider) *schema.Set {
+    s := schema.NewSet(cognitoIdentityProvidersHash, []interface{}{})
+    for _, p := range providers {
+        s.Add(map[string]interface{}{
+            "client_id": aws.StringValue(p.ClientId),
+            "provider_name": aws.StringValue(p.ProviderName),
+            "server_side_token_check": aws.BoolValue(p.ServerSideTokenCheck),
+        })
+    }
+    return s
+}
+
+func cognitoIdentityProvidersHash(v interface{}) int {
+    var buf bytes.Buffer
+    m := v.(map[string]interface{})
+    buf.WriteString(fmt.Sprintf("%s-", m["client_id"].(string)))
+    buf.WriteString(fmt.Sprintf("%s-", m["provider_name"].(string)))
+    buf.WriteString(fmt.Sprintf("%t-", m["server_side_token_check"].(bool)))
+    return hashcode.String(buf.String())
+}
+
+func validateCognitoIdentityPoolName(v interface{}, k string) (ws []string, errors []error) {
+    value := v.(string)
+    if !regexp.MustCompile(`^[\w\s+=,.@-]{1,128}$`).MatchString(value) {
+        errors = append(errors, fmt.Errorf("%q must be 1 to 128 letters, digits, spaces or +=,.@-_ characters", k))
+    }
+    return
+}
@@ -0,0 +1,135 @@
+package aws
+
+import (
+    "fmt"
+    "log"
+    "strings"
+
+    "github.com/hashicorp/terraform/helper/schema"
+
+    "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cognitoidentity"
+)
+
+func resourceTrilityAwsCognitoIdentityPoolPrincipalTags() *schema.Resource {
+    return &schema.Resource{
+        Create: resourceCognitoIdentityPoolPrincipalTagsPut,
+        Read: resourceCognitoIdentityPoolPrincipalTagsRead,
+        Update: resourceCognitoIdentityPoolPrincipalTagsPut,
+        Delete: resourceCognitoIdentityPoolPrincipalTagsDelete,
+        Importer: &schema.ResourceImporter{
+            State: schema.ImportStatePassthrough,
+        },
+
+        Schema: map[string]*schema.Schema{
+            "identity_pool_id": &schema.Schema{
+                Type: schema.TypeString,
+                Required: true,
+                ForceNew: true,
+            },
+            "identity_provider_name": &schema.Schema{
+                Type: schema.TypeString,
+                Required: true,
+                ForceNew: true,
+            },
+            // Session tag names mapped to the token claims they are taken from
+            "principal_tags": &schema.Schema{
+                Type: schema.TypeMap,
+                Optional: true,
+                Elem: &schema.Schema{Type: schema.TypeString},
+            },
+            // Use Cognito's default mapping of the aud and sub claims
+            "use_defaults": &schema.Schema{
+                Type: schema.TypeBool,
+                Optional: true,
+                Default: false,
+            },
+        },
+    }
+}
+
+func resourceCognitoIdentityPoolPrincipalTagsPut(d *schema.ResourceData, meta interface{}) error {
+    cognitoconn := meta.(*AWSClient).cognitoconn
+    identityPoolId := d.Get("identity_pool_id").(string)
+    providerName := d.Get("identity_provider_name").(string)
+    id := fmt.Sprintf("%s/%s", identityPoolId, providerName)
+
+    params := &cognitoidentity.SetPrincipalTagAttributeMapInput{
+        IdentityPoolId: aws.String(identityPoolId),
+        IdentityProviderName: aws.String(providerName),
+        PrincipalTags: stringMapToPointers(d.Get("principal_tags").(map[string]interface{})),
+        UseDefaults: aws.Bool(d.Get("use_defaults").(bool)),
+    }
+
+    _, err := cognitoconn.SetPrincipalTagAttributeMap(params)
+    if err != nil {
+        return fmt.Errorf("Error setting principal tags %s: %s", id, err)
+    }
+
+    d.SetId(id)
+    return resourceCognitoIdentityPoolPrincipalTagsRead(d, meta)
+}
+
+func resourceCognitoIdentityPoolPrincipalTagsRead(d *schema.ResourceData, meta interface{}) error {
+    cognitoconn := meta.(*AWSClient).cognitoconn
+    id := d.Id()
+
+    identityPoolId, providerName, err := decodeCognitoIdentityPoolPrincipalTagsId(id)
+    if err != nil {
+        return err
+    }
+
+    resp, err := cognitoconn.GetPrincipalTagAttributeMap(&cognitoidentity.GetPrincipalTagAttributeMapInput{
+        IdentityPoolId: aws.String(identityPoolId),
+        IdentityProviderName: aws.String(providerName),
+    })
+    if err != nil {
+        if isAWSErr(err, cognitoidentity.ErrCodeResourceNotFoundException, "") {
+            log.Printf("[WARN] Principal tags %s not found, removing from state", id)
+            d.SetId("")
+            return nil
+        }
+        return fmt.Errorf("Error reading principal tags %s: %s", id, err)
+    }
+
+    d.Set("identity_pool_id", identityPoolId)
+    d.Set("identity_provider_name", providerName)
+    d.Set("use_defaults", resp.UseDefaults)
+
+    if err := d.Set("principal_tags", pointersMapToStringList(resp.PrincipalTags)); err != nil {
+        return fmt.Errorf("Error setting principal_tags for %s: %s", id, err)
+    }
+
+    return nil
+}
+
+func resourceCognitoIdentityPoolPrincipalTagsDelete(d *schema.ResourceData, meta interface{}) error {
+    cognitoconn := meta.(*AWSClient).cognitoconn
+    id := d.Id()
+
+    // There is no delete call, an empty map without defaults turns the
+    // mapping off
+    params := &cognitoidentity.SetPrincipalTagAttributeMapInput{
+        IdentityPoolId: aws.String(d.Get("identity_pool_id").(string)),
+        IdentityProviderName: aws.String(d.Get("identity_provider_name").(string)),
+        PrincipalTags: map[string]*string{},
+        UseDefaults: aws.Bool(false),
+    }
+
+    _, err := cognitoconn.SetPrincipalTagAttributeMap(params)
+    if err != nil && !isAWSErr(err, cognitoidentity.ErrCodeResourceNotFoundException, "") {
+        return fmt.Errorf("Error removing principal tags %s: %s", id, err)
+    }
+
+    return nil
+}
+
+// The ID, and the import ID, is identity_pool_id/identity_provider_name.
+// Provider names may contain slashes, identity pool IDs never do.
+func decodeCognitoIdentityPoolPrincipalTagsId(id string) (string, string, error) {
+    parts := strings.SplitN(id, "/", 2)
+    if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+        return "", "", fmt.Errorf("Wrong format of principal tags ID (%s), use: 'identity-pool-id/identity-provider-name'", id)
+    }
+    return parts[0], parts[1], nil
+}
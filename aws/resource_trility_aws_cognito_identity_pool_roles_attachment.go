@@ -0,0 +1,309 @@
+package aws
+
+import (
+    "fmt"
+    "log"
+
+    "github.com/hashicorp/terraform/helper/schema"
+    "github.com/hashicorp/terraform/helper/validation"
+
+    "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cognitoidentity"
+)
+
+func resourceTrilityAwsCognitoIdentityPoolRolesAttachment() *schema.Resource {
+    return &schema.Resource{
+        Create: resourceCognitoIdentityPoolRolesAttachmentPut,
+        Read: resourceCognitoIdentityPoolRolesAttachmentRead,
+        Update: resourceCognitoIdentityPoolRolesAttachmentPut,
+        Delete: resourceCognitoIdentityPoolRolesAttachmentDelete,
+        Importer: &schema.ResourceImporter{
+            State: schema.ImportStatePassthrough,
+        },
+
+        CustomizeDiff: resourceCognitoIdentityPoolRolesAttachmentCustomizeDiff,
+
+        Schema: map[string]*schema.Schema{
+            "identity_pool_id": &schema.Schema{
+                Type: schema.TypeString,
+                Required: true,
+                ForceNew: true,
+            },
+            // Role ARNs keyed by authenticated and unauthenticated
+            "roles": &schema.Schema{
+                Type: schema.TypeMap,
+                Required: true,
+                Elem: &schema.Schema{Type: schema.TypeString},
+                ValidateFunc: validateCognitoIdentityPoolRoles,
+            },
+            "role_mapping": &schema.Schema{
+                Type: schema.TypeSet,
+                Optional: true,
+                Elem: &schema.Resource{
+                    Schema: map[string]*schema.Schema{
+                        // A provider configured on the pool, e.g.
+                        // cognito-idp.<region>.amazonaws.com/<user pool id>:<client id>
+                        "identity_provider": {
+                            Type: schema.TypeString,
+                            Required: true,
+                        },
+                        "type": {
+                            Type: schema.TypeString,
+                            Required: true,
+                            ValidateFunc: validation.StringInSlice([]string{
+                                cognitoidentity.RoleMappingTypeToken,
+                                cognitoidentity.RoleMappingTypeRules,
+                            }, false),
+                        },
+                        // What happens when no rule matches, or the token
+                        // carries no usable cognito:preferred_role
+                        "ambiguous_role_resolution": {
+                            Type: schema.TypeString,
+                            Required: true,
+                            ValidateFunc: validation.StringInSlice([]string{
+                                cognitoidentity.AmbiguousRoleResolutionTypeAuthenticatedRole,
+                                cognitoidentity.AmbiguousRoleResolutionTypeDeny,
+                            }, false),
+                        },
+                        // Rules are evaluated in order, the first match wins
+                        "mapping_rule": {
+                            Type: schema.TypeList,
+                            Optional: true,
+                            MaxItems: 25,
+                            Elem: &schema.Resource{
+                                Schema: map[string]*schema.Schema{
+                                    "claim": {
+                                        Type: schema.TypeString,
+                                        Required: true,
+                                    },
+                                    "match_type": {
+                                        Type: schema.TypeString,
+                                        Required: true,
+                                        ValidateFunc: validation.StringInSlice([]string{
+                                            cognitoidentity.MappingRuleMatchTypeEquals,
+                                            cognitoidentity.MappingRuleMatchTypeContains,
+                                            cognitoidentity.MappingRuleMatchTypeStartsWith,
+                                            cognitoidentity.MappingRuleMatchTypeNotEqual,
+                                        }, false),
+                                    },
+                                    "value": {
+                                        Type: schema.TypeString,
+                                        Required: true,
+                                    },
+                                    "role_arn": {
+                                        Type: schema.TypeString,
+                                        Required: true,
+                                    },
+                                },
+                            },
+                        },
+                    },
+                },
+            },
+        },
+    }
+}
+
+func resourceCognitoIdentityPoolRolesAttachmentPut(d *schema.ResourceData, meta interface{}) error {
+    cognitoconn := meta.(*AWSClient).cognitoconn
+    id := d.Get("identity_pool_id").(string)
+
+    if err := checkCognitoIdentityPoolRoleMappingProviders(cognitoconn, id, d.Get("role_mapping").(*schema.Set)); err != nil {
+        return err
+    }
+
+    params := &cognitoidentity.SetIdentityPoolRolesInput{
+        IdentityPoolId: aws.String(id),
+        Roles: stringMapToPointers(d.Get("roles").(map[string]interface{})),
+        RoleMappings: expandCognitoIdentityPoolRoleMappings(d.Get("role_mapping").(*schema.Set)),
+    }
+
+    _, err := cognitoconn.SetIdentityPoolRoles(params)
+    if err != nil {
+        return fmt.Errorf("Error setting roles of Identity Pool %s: %s", id, err)
+    }
+
+    d.SetId(id)
+    return resourceCognitoIdentityPoolRolesAttachmentRead(d, meta)
+}
+
+func resourceCognitoIdentityPoolRolesAttachmentRead(d *schema.ResourceData, meta interface{}) error {
+    cognitoconn := meta.(*AWSClient).cognitoconn
+    id := d.Id()
+
+    resp, err := cognitoconn.GetIdentityPoolRoles(&cognitoidentity.GetIdentityPoolRolesInput{
+        IdentityPoolId: aws.String(id),
+    })
+    if err != nil {
+        if isAWSErr(err, cognitoidentity.ErrCodeResourceNotFoundException, "") {
+            log.Printf("[WARN] Identity Pool %s not found, removing roles attachment from state", id)
+            d.SetId("")
+            return nil
+        }
+        return fmt.Errorf("Error reading roles of Identity Pool %s: %s", id, err)
+    }
+
+    d.Set("identity_pool_id", id)
+
+    if err := d.Set("roles", pointersMapToStringList(resp.Roles)); err != nil {
+        return fmt.Errorf("Error setting roles for Identity Pool %s: %s", id, err)
+    }
+
+    if err := d.Set("role_mapping", flattenCognitoIdentityPoolRoleMappings(resp.RoleMappings)); err != nil {
+        return fmt.Errorf("Error setting role_mapping for Identity Pool %s: %s", id, err)
+    }
+
+    return nil
+}
+
+func resourceCognitoIdentityPoolRolesAttachmentDelete(d *schema.ResourceData, meta interface{}) error {
+    cognitoconn := meta.(*AWSClient).cognitoconn
+    id := d.Id()
+
+    // Roles is required, an empty map detaches everything
+    params := &cognitoidentity.SetIdentityPoolRolesInput{
+        IdentityPoolId: aws.String(id),
+        Roles: map[string]*string{},
+        RoleMappings: map[string]*cognitoidentity.RoleMapping{},
+    }
+
+    _, err := cognitoconn.SetIdentityPoolRoles(params)
+    if err != nil && !isAWSErr(err, cognitoidentity.ErrCodeResourceNotFoundException, "") {
+        return fmt.Errorf("Error removing roles of Identity Pool %s: %s", id, err)
+    }
+
+    return nil
+}
+
+// resourceCognitoIdentityPoolRolesAttachmentCustomizeDiff checks the shape of
+// every role mapping. Whether the providers exist on the pool is only known
+// once changes to the pool in the same plan are applied, so that is checked
+// by checkCognitoIdentityPoolRoleMappingProviders at apply time.
+func resourceCognitoIdentityPoolRolesAttachmentCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+    for _, raw := range d.Get("role_mapping").(*schema.Set).List() {
+        m := raw.(map[string]interface{})
+        provider := m["identity_provider"].(string)
+        rules := m["mapping_rule"].([]interface{})
+
+        switch m["type"].(string) {
+        case cognitoidentity.RoleMappingTypeRules:
+            if len(rules) == 0 {
+                return fmt.Errorf("role_mapping for %s: type Rules needs at least one mapping_rule", provider)
+            }
+        case cognitoidentity.RoleMappingTypeToken:
+            if len(rules) > 0 {
+                return fmt.Errorf("role_mapping for %s: mapping_rule cannot be used with type Token", provider)
+            }
+        }
+    }
+
+    return nil
+}
+
+// checkCognitoIdentityPoolRoleMappingProviders makes sure every role mapping
+// refers to a provider configured on the pool, which SetIdentityPoolRoles
+// would otherwise reject with a far less helpful message
+func checkCognitoIdentityPoolRoleMappingProviders(cognitoconn *cognitoidentity.CognitoIdentity, id string, mappings *schema.Set) error {
+    if mappings.Len() == 0 {
+        return nil
+    }
+
+    pool, err := cognitoconn.DescribeIdentityPool(&cognitoidentity.DescribeIdentityPoolInput{
+        IdentityPoolId: aws.String(id),
+    })
+    if err != nil {
+        return fmt.Errorf("Error reading Identity Pool %s: %s", id, err)
+    }
+
+    configured := make(map[string]bool)
+    for _, p := range pool.CognitoIdentityProviders {
+        configured[fmt.Sprintf("%s:%s", aws.StringValue(p.ProviderName), aws.StringValue(p.ClientId))] = true
+    }
+    for _, arn := range pool.OpenIdConnectProviderARNs {
+        configured[aws.StringValue(arn)] = true
+    }
+    for _, arn := range pool.SamlProviderARNs {
+        configured[aws.StringValue(arn)] = true
+    }
+    for k := range pool.SupportedLoginProviders {
+        configured[k] = true
+    }
+
+    for _, raw := range mappings.List() {
+        provider := raw.(map[string]interface{})["identity_provider"].(string)
+        if !configured[provider] {
+            return fmt.Errorf("role_mapping refers to %s, which is not configured on Identity Pool %s", provider, id)
+        }
+    }
+
+    return nil
+}
+
+func expandCognitoIdentityPoolRoleMappings(s *schema.Set) map[string]*cognitoidentity.RoleMapping {
+    mappings := make(map[string]*cognitoidentity.RoleMapping, s.Len())
+    for _, raw := range s.List() {
+        m := raw.(map[string]interface{})
+
+        mapping := &cognitoidentity.RoleMapping{
+            Type: aws.String(m["type"].(string)),
+            AmbiguousRoleResolution: aws.String(m["ambiguous_role_resolution"].(string)),
+        }
+
+        if rules := m["mapping_rule"].([]interface{}); len(rules) > 0 {
+            mapping.RulesConfiguration = &cognitoidentity.RulesConfigurationType{
+                Rules: expandCognitoIdentityPoolMappingRules(rules),
+            }
+        }
+
+        mappings[m["identity_provider"].(string)] = mapping
+    }
+    return mappings
+}
+
+func expandCognitoIdentityPoolMappingRules(l []interface{}) []*cognitoidentity.MappingRule {
+    rules := make([]*cognitoidentity.MappingRule, 0, len(l))
+    for _, raw := range l {
+        m := raw.(map[string]interface{})
+        rules = append(rules, &cognitoidentity.MappingRule{
+            Claim: aws.String(m["claim"].(string)),
+            MatchType: aws.String(m["match_type"].(string)),
+            Value: aws.String(m["value"].(string)),
+            RoleARN: aws.String(m["role_arn"].(string)),
+        })
+    }
+    return rules
+}
+
+func flattenCognitoIdentityPoolRoleMappings(mappings map[string]*cognitoidentity.RoleMapping) []map[string]interface{} {
+    l := make([]map[string]interface{}, 0, len(mappings))
+    for provider, mapping := range mappings {
+        rules := make([]map[string]interface{}, 0)
+        if mapping.RulesConfiguration != nil {
+            for _, r := range mapping.RulesConfiguration.Rules {
+                rules = append(rules, map[string]interface{}{
+                    "claim": aws.StringValue(r.Claim),
+                    "match_type": aws.StringValue(r.MatchType),
+                    "value": aws.StringValue(r.Value),
+                    "role_arn": aws.StringValue(r.RoleARN),
+                })
+            }
+        }
+
+        l = append(l, map[string]interface{}{
+            "identity_provider": provider,
+            "type": aws.StringValue(mapping.Type),
+            "ambiguous_role_resolution": aws.StringValue(mapping.AmbiguousRoleResolution),
+            "mapping_rule": rules,
+        })
+    }
+    return l
+}
+
+func validateCognitoIdentityPoolRoles(v interface{}, k string) (ws []string, errors []error) {
+    for role := range v.(map[string]interface{}) {
+        if role != "authenticated" && role != "unauthenticated" {
+            errors = append(errors, fmt.Errorf("%q can only contain the keys authenticated and unauthenticated, got %s", k, role))
+        }
+    }
+    return
+}
@@ -0,0 +1,244 @@
+package aws
+
+import (
+    "fmt"
+    "log"
+    "strings"
+
+    "github.com/hashicorp/terraform/helper/schema"
+    "github.com/hashicorp/terraform/helper/validation"
+
+    "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
+)
+
+// provider_details keys Cognito fills in by itself, e.g. the endpoints it
+// discovers for OIDC and social providers or the metadata it downloads from
+// a SAML MetadataURL. They are only tracked when the configuration sets them.
+var cognitoIDPServerPopulatedProviderDetails = []string{
+    "ActiveEncryptionCertificate",
+    "MetadataFile",
+    "SLORedirectBindingURI",
+    "SSORedirectBindingURI",
+    "attributes_url",
+    "attributes_url_add_attributes",
+    "authorize_url",
+    "jwks_uri",
+    "oidc_issuer",
+    "token_request_method",
+    "token_url",
+}
+
+func resourceTrilityAwsCognitoIdentityProvider() *schema.Resource {
+    return &schema.Resource{
+        Create: resourceCognitoIDPIdentityProviderCreate,
+        Read: resourceCognitoIDPIdentityProviderRead,
+        Update: resourceCognitoIDPIdentityProviderUpdate,
+        Delete: resourceCognitoIDPIdentityProviderDelete,
+        Importer: &schema.ResourceImporter{
+            State: schema.ImportStatePassthrough,
+        },
+
+        CustomizeDiff: resourceCognitoIDPIdentityProviderCustomizeDiff,
+
+        Schema: map[string]*schema.Schema{
+            "user_pool_id": &schema.Schema{
+                Type: schema.TypeString,
+                Required: true,
+                ForceNew: true,
+            },
+            "provider_name": &schema.Schema{
+                Type: schema.TypeString,
+                Required: true,
+                ForceNew: true,
+            },
+            "provider_type": &schema.Schema{
+                Type: schema.TypeString,
+                Required: true,
+                ForceNew: true,
+                ValidateFunc: validation.StringInSlice([]string{
+                    cognitoidentityprovider.IdentityProviderTypeTypeSaml,
+                    cognitoidentityprovider.IdentityProviderTypeTypeOidc,
+                    cognitoidentityprovider.IdentityProviderTypeTypeFacebook,
+                    cognitoidentityprovider.IdentityProviderTypeTypeGoogle,
+                    cognitoidentityprovider.IdentityProviderTypeTypeLoginWithAmazon,
+                    cognitoidentityprovider.IdentityProviderTypeTypeSignInWithApple,
+                }, false),
+            },
+            // SAML metadata goes in as either MetadataFile (inline XML) or
+            // MetadataURL
+            "provider_details": &schema.Schema{
+                Type: schema.TypeMap,
+                Required: true,
+                Elem: &schema.Schema{Type: schema.TypeString},
+            },
+            "attribute_mapping": &schema.Schema{
+                Type: schema.TypeMap,
+                Optional: true,
+                Computed: true,
+                Elem: &schema.Schema{Type: schema.TypeString},
+            },
+            "idp_identifiers": &schema.Schema{
+                Type: schema.TypeList,
+                Optional: true,
+                MaxItems: 50,
+                Elem: &schema.Schema{Type: schema.TypeString},
+            },
+        },
+    }
+}
+
+func resourceCognitoIDPIdentityProviderCreate(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    userPoolId := d.Get("user_pool_id").(string)
+    providerName := d.Get("provider_name").(string)
+
+    params := &cognitoidentityprovider.CreateIdentityProviderInput{
+        UserPoolId: aws.String(userPoolId),
+        ProviderName: aws.String(providerName),
+        ProviderType: aws.String(d.Get("provider_type").(string)),
+        ProviderDetails: stringMapToPointers(d.Get("provider_details").(map[string]interface{})),
+    }
+
+    if v, ok := d.GetOk("attribute_mapping"); ok {
+        params.AttributeMapping = stringMapToPointers(v.(map[string]interface{}))
+    }
+
+    if v, ok := d.GetOk("idp_identifiers"); ok {
+        params.IdpIdentifiers = expandStringList(v.([]interface{}))
+    }
+
+    _, err := cidpconn.CreateIdentityProvider(params)
+    if err != nil {
+        return fmt.Errorf("Error creating Identity Provider %s: %s", providerName, err)
+    }
+
+    d.SetId(fmt.Sprintf("%s/%s", userPoolId, providerName))
+    return resourceCognitoIDPIdentityProviderRead(d, meta)
+}
+
+func resourceCognitoIDPIdentityProviderRead(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    id := d.Id()
+
+    userPoolId, providerName, err := decodeCognitoIDPIdentityProviderId(id)
+    if err != nil {
+        return err
+    }
+
+    params := &cognitoidentityprovider.DescribeIdentityProviderInput{
+        UserPoolId: aws.String(userPoolId),
+        ProviderName: aws.String(providerName),
+    }
+
+    resp, err := cidpconn.DescribeIdentityProvider(params)
+    if err != nil {
+        if isAWSErr(err, cognitoidentityprovider.ErrCodeResourceNotFoundException, "") {
+            log.Printf("[WARN] Identity Provider %s not found, removing from state", id)
+            d.SetId("")
+            return nil
+        }
+        return fmt.Errorf("Error reading Identity Provider %s: %s", id, err)
+    }
+
+    idp := resp.IdentityProvider
+    d.Set("user_pool_id", idp.UserPoolId)
+    d.Set("provider_name", idp.ProviderName)
+    d.Set("provider_type", idp.ProviderType)
+
+    details := pointersMapToStringList(idp.ProviderDetails)
+    configured := d.Get("provider_details").(map[string]interface{})
+    for _, k := range cognitoIDPServerPopulatedProviderDetails {
+        if _, ok := configured[k]; !ok {
+            delete(details, k)
+        }
+    }
+    if err := d.Set("provider_details", details); err != nil {
+        return fmt.Errorf("Error setting provider_details for Identity Provider %s: %s", id, err)
+    }
+
+    if err := d.Set("attribute_mapping", pointersMapToStringList(idp.AttributeMapping)); err != nil {
+        return fmt.Errorf("Error setting attribute_mapping for Identity Provider %s: %s", id, err)
+    }
+
+    if err := d.Set("idp_identifiers", flattenStringList(idp.IdpIdentifiers)); err != nil {
+        return fmt.Errorf("Error setting idp_identifiers for Identity Provider %s: %s", id, err)
+    }
+
+    return nil
+}
+
+func resourceCognitoIDPIdentityProviderUpdate(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    id := d.Id()
+
+    params := &cognitoidentityprovider.UpdateIdentityProviderInput{
+        UserPoolId: aws.String(d.Get("user_pool_id").(string)),
+        ProviderName: aws.String(d.Get("provider_name").(string)),
+    }
+
+    if d.HasChange("provider_details") {
+        params.ProviderDetails = stringMapToPointers(d.Get("provider_details").(map[string]interface{}))
+    }
+
+    if d.HasChange("attribute_mapping") {
+        params.AttributeMapping = stringMapToPointers(d.Get("attribute_mapping").(map[string]interface{}))
+    }
+
+    if d.HasChange("idp_identifiers") {
+        params.IdpIdentifiers = expandStringList(d.Get("idp_identifiers").([]interface{}))
+    }
+
+    _, err := cidpconn.UpdateIdentityProvider(params)
+    if err != nil {
+        return fmt.Errorf("Error updating Identity Provider %s: %s", id, err)
+    }
+
+    return resourceCognitoIDPIdentityProviderRead(d, meta)
+}
+
+func resourceCognitoIDPIdentityProviderDelete(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    id := d.Id()
+
+    params := &cognitoidentityprovider.DeleteIdentityProviderInput{
+        UserPoolId: aws.String(d.Get("user_pool_id").(string)),
+        ProviderName: aws.String(d.Get("provider_name").(string)),
+    }
+
+    _, err := cidpconn.DeleteIdentityProvider(params)
+    if err != nil && !isAWSErr(err, cognitoidentityprovider.ErrCodeResourceNotFoundException, "") {
+        return fmt.Errorf("Error removing Identity Provider %s: %s", id, err)
+    }
+
+    return nil
+}
+
+func resourceCognitoIDPIdentityProviderCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+    if d.Get("provider_type").(string) != cognitoidentityprovider.IdentityProviderTypeTypeSaml {
+        return nil
+    }
+
+    if !d.NewValueKnown("provider_details") {
+        return nil
+    }
+
+    details := d.Get("provider_details").(map[string]interface{})
+    _, file := details["MetadataFile"]
+    _, url := details["MetadataURL"]
+    if file == url {
+        return fmt.Errorf("SAML provider_details must contain exactly one of MetadataFile or MetadataURL")
+    }
+
+    return nil
+}
+
+// The ID, and the import ID, is user_pool_id/provider_name. Pool IDs never
+// contain a slash, so everything after the first one is the provider name.
+func decodeCognitoIDPIdentityProviderId(id string) (string, string, error) {
+    parts := strings.SplitN(id, "/", 2)
+    if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+        return "", "", fmt.Errorf("Wrong format of Identity Provider ID (%s), use: 'user-pool-id/provider-name'", id)
+    }
+    return parts[0], parts[1], nil
+}
@@ -0,0 +1,309 @@
+package aws
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "io/ioutil"
+    "log"
+
+    "github.com/hashicorp/terraform/helper/schema"
+    "github.com/hashicorp/terraform/helper/structure"
+    "github.com/hashicorp/terraform/helper/validation"
+
+    "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
+)
+
+// Managed login branding replaces the CSS and logo of the classic hosted UI
+// (see trility_aws_cognito_user_pool_ui_customization) for domains using
+// managed login version 2
+func resourceTrilityAwsCognitoManagedLoginBranding() *schema.Resource {
+    return &schema.Resource{
+        Create: resourceCognitoIDPManagedLoginBrandingCreate,
+        Read: resourceCognitoIDPManagedLoginBrandingRead,
+        Update: resourceCognitoIDPManagedLoginBrandingUpdate,
+        Delete: resourceCognitoIDPManagedLoginBrandingDelete,
+
+        CustomizeDiff: resourceCognitoIDPManagedLoginBrandingCustomizeDiff,
+
+        Schema: map[string]*schema.Schema{
+            "user_pool_id": &schema.Schema{
+                Type: schema.TypeString,
+                Required: true,
+                ForceNew: true,
+            },
+            "client_id": &schema.Schema{
+                Type: schema.TypeString,
+                Required: true,
+                ForceNew: true,
+            },
+            "settings": &schema.Schema{
+                Type: schema.TypeString,
+                Optional: true,
+                ValidateFunc: validation.ValidateJsonString,
+                DiffSuppressFunc: suppressEquivalentJsonDiffs,
+                StateFunc: func(v interface{}) string {
+                    json, _ := structure.NormalizeJsonString(v)
+                    return json
+                },
+            },
+            "use_cognito_provided_values": &schema.Schema{
+                Type: schema.TypeBool,
+                Optional: true,
+                Default: false,
+            },
+            "asset": &schema.Schema{
+                Type: schema.TypeList,
+                Optional: true,
+                MaxItems: 40,
+                Elem: &schema.Resource{
+                    Schema: map[string]*schema.Schema{
+                        "category": {
+                            Type: schema.TypeString,
+                            Required: true,
+                            ValidateFunc: validation.StringInSlice([]string{
+                                cognitoidentityprovider.AssetCategoryTypeFaviconIco,
+                                cognitoidentityprovider.AssetCategoryTypeFaviconSvg,
+                                cognitoidentityprovider.AssetCategoryTypeEmailGraphic,
+                                cognitoidentityprovider.AssetCategoryTypeSmsGraphic,
+                                cognitoidentityprovider.AssetCategoryTypeAuthAppGraphic,
+                                cognitoidentityprovider.AssetCategoryTypePasswordGraphic,
+                                cognitoidentityprovider.AssetCategoryTypePasskeyGraphic,
+                                cognitoidentityprovider.AssetCategoryTypePageHeaderLogo,
+                                cognitoidentityprovider.AssetCategoryTypePageHeaderBackground,
+                                cognitoidentityprovider.AssetCategoryTypePageFooterLogo,
+                                cognitoidentityprovider.AssetCategoryTypePageFooterBackground,
+                                cognitoidentityprovider.AssetCategoryTypePageBackground,
+                                cognitoidentityprovider.AssetCategoryTypeFormBackground,
+                                cognitoidentityprovider.AssetCategoryTypeFormLogo,
+                                cognitoidentityprovider.AssetCategoryTypeIdpButtonIcon,
+                            }, false),
+                        },
+                        "color_mode": {
+                            Type: schema.TypeString,
+                            Required: true,
+                            ValidateFunc: validation.StringInSlice([]string{
+                                cognitoidentityprovider.ColorSchemeModeTypeLight,
+                                cognitoidentityprovider.ColorSchemeModeTypeDark,
+                                cognitoidentityprovider.ColorSchemeModeTypeDynamic,
+                            }, false),
+                        },
+                        "extension": {
+                            Type: schema.TypeString,
+                            Required: true,
+                            ValidateFunc: validation.StringInSlice([]string{
+                                cognitoidentityprovider.AssetExtensionTypeIco,
+                                cognitoidentityprovider.AssetExtensionTypeJpeg,
+                                cognitoidentityprovider.AssetExtensionTypePng,
+                                cognitoidentityprovider.AssetExtensionTypeSvg,
+                                cognitoidentityprovider.AssetExtensionTypeWebp,
+                            }, false),
+                        },
+                        // Path to the local image file
+                        "file": {
+                            Type: schema.TypeString,
+                            Required: true,
+                        },
+                        "resource_id": {
+                            Type: schema.TypeString,
+                            Optional: true,
+                        },
+                    },
+                },
+            },
+            // Hash over the content of every asset file, so that edits to
+            // the images show up in the plan
+            "assets_hash": &schema.Schema{
+                Type: schema.TypeString,
+                Computed: true,
+            },
+            "managed_login_branding_id": &schema.Schema{
+                Type: schema.TypeString,
+                Computed: true,
+            },
+        },
+    }
+}
+
+func resourceCognitoIDPManagedLoginBrandingCreate(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    clientId := d.Get("client_id").(string)
+
+    params := &cognitoidentityprovider.CreateManagedLoginBrandingInput{
+        UserPoolId: aws.String(d.Get("user_pool_id").(string)),
+        ClientId: aws.String(clientId),
+        UseCognitoProvidedValues: aws.Bool(d.Get("use_cognito_provided_values").(bool)),
+    }
+
+    if v, ok := d.GetOk("settings"); ok {
+        settings, err := expandManagedLoginBrandingSettings(v.(string))
+        if err != nil {
+            return err
+        }
+        params.Settings = settings
+    }
+
+    assets, hash, err := expandManagedLoginBrandingAssets(d.Get("asset").([]interface{}))
+    if err != nil {
+        return err
+    }
+    params.Assets = assets
+
+    resp, err := cidpconn.CreateManagedLoginBranding(params)
+    if err != nil {
+        return fmt.Errorf("Error creating managed login branding for client %s: %s", clientId, err)
+    }
+
+    d.SetId(*resp.ManagedLoginBranding.ManagedLoginBrandingId)
+    d.Set("assets_hash", hash)
+    return resourceCognitoIDPManagedLoginBrandingRead(d, meta)
+}
+
+func resourceCognitoIDPManagedLoginBrandingRead(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    id := d.Id()
+
+    params := &cognitoidentityprovider.DescribeManagedLoginBrandingInput{
+        UserPoolId: aws.String(d.Get("user_pool_id").(string)),
+        ManagedLoginBrandingId: aws.String(id),
+    }
+
+    resp, err := cidpconn.DescribeManagedLoginBranding(params)
+    if err != nil {
+        if isAWSErr(err, cognitoidentityprovider.ErrCodeResourceNotFoundException, "") {
+            log.Printf("[WARN] Managed login branding %s not found, removing from state", id)
+            d.SetId("")
+            return nil
+        }
+        return fmt.Errorf("Error reading managed login branding %s: %s", id, err)
+    }
+
+    branding := resp.ManagedLoginBranding
+    d.Set("managed_login_branding_id", branding.ManagedLoginBrandingId)
+    d.Set("use_cognito_provided_values", branding.UseCognitoProvidedValues)
+
+    if len(branding.Settings) > 0 {
+        settings, err := json.Marshal(branding.Settings)
+        if err != nil {
+            return fmt.Errorf("Error encoding settings of managed login branding %s: %s", id, err)
+        }
+        d.Set("settings", string(settings))
+    } else {
+        d.Set("settings", "")
+    }
+
+    return nil
+}
+
+func resourceCognitoIDPManagedLoginBrandingUpdate(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    id := d.Id()
+
+    params := &cognitoidentityprovider.UpdateManagedLoginBrandingInput{
+        UserPoolId: aws.String(d.Get("user_pool_id").(string)),
+        ManagedLoginBrandingId: aws.String(id),
+        UseCognitoProvidedValues: aws.Bool(d.Get("use_cognito_provided_values").(bool)),
+    }
+
+    if v, ok := d.GetOk("settings"); ok {
+        settings, err := expandManagedLoginBrandingSettings(v.(string))
+        if err != nil {
+            return err
+        }
+        params.Settings = settings
+    }
+
+    assets, hash, err := expandManagedLoginBrandingAssets(d.Get("asset").([]interface{}))
+    if err != nil {
+        return err
+    }
+    params.Assets = assets
+
+    _, err = cidpconn.UpdateManagedLoginBranding(params)
+    if err != nil {
+        return fmt.Errorf("Error updating managed login branding %s: %s", id, err)
+    }
+
+    d.Set("assets_hash", hash)
+    return resourceCognitoIDPManagedLoginBrandingRead(d, meta)
+}
+
+func resourceCognitoIDPManagedLoginBrandingDelete(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    id := d.Id()
+
+    params := &cognitoidentityprovider.DeleteManagedLoginBrandingInput{
+        UserPoolId: aws.String(d.Get("user_pool_id").(string)),
+        ManagedLoginBrandingId: aws.String(id),
+    }
+
+    _, err := cidpconn.DeleteManagedLoginBranding(params)
+    if err != nil && !isAWSErr(err, cognitoidentityprovider.ErrCodeResourceNotFoundException, "") {
+        return fmt.Errorf("Error removing managed login branding %s: %s", id, err)
+    }
+
+    return nil
+}
+
+func resourceCognitoIDPManagedLoginBrandingCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+    if !d.NewValueKnown("asset") {
+        return d.SetNewComputed("assets_hash")
+    }
+
+    _, hash, err := expandManagedLoginBrandingAssets(d.Get("asset").([]interface{}))
+    if err != nil {
+        return err
+    }
+
+    if hash != d.Get("assets_hash").(string) {
+        return d.SetNew("assets_hash", hash)
+    }
+
+    return nil
+}
+
+func expandManagedLoginBrandingSettings(s string) (aws.JSONValue, error) {
+    var settings aws.JSONValue
+    if err := json.Unmarshal([]byte(s), &settings); err != nil {
+        return nil, fmt.Errorf("Error decoding managed login branding settings: %s", err)
+    }
+    return settings, nil
+}
+
+// expandManagedLoginBrandingAssets reads every asset file and returns the
+// assets together with a hash over their content
+func expandManagedLoginBrandingAssets(l []interface{}) ([]*cognitoidentityprovider.AssetType, string, error) {
+    assets := make([]*cognitoidentityprovider.AssetType, 0, len(l))
+    var buf bytes.Buffer
+
+    for _, raw := range l {
+        m := raw.(map[string]interface{})
+        file := m["file"].(string)
+
+        content, err := ioutil.ReadFile(file)
+        if err != nil {
+            return nil, "", fmt.Errorf("Error reading asset file %s: %s", file, err)
+        }
+
+        asset := &cognitoidentityprovider.AssetType{
+            Category: aws.String(m["category"].(string)),
+            ColorMode: aws.String(m["color_mode"].(string)),
+            Extension: aws.String(m["extension"].(string)),
+            Bytes: content,
+        }
+        if v, ok := m["resource_id"].(string); ok && v != "" {
+            asset.ResourceId = aws.String(v)
+        }
+        assets = append(assets, asset)
+
+        buf.WriteString(fmt.Sprintf("%s-%s-", m["category"].(string), m["color_mode"].(string)))
+        buf.Write(content)
+    }
+
+    if len(assets) == 0 {
+        return assets, "", nil
+    }
+
+    return assets, sha256Hex(buf.Bytes()), nil
+}
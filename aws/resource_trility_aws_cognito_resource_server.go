@@ -0,0 +1,197 @@
+package aws
+
+import (
+    "fmt"
+    "log"
+    "strings"
+
+    "github.com/hashicorp/terraform/helper/schema"
+
+    "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
+)
+
+func resourceTrilityAwsCognitoResourceServer() *schema.Resource {
+    return &schema.Resource{
+        Create: resourceCognitoIDPResourceServerCreate,
+        Read: resourceCognitoIDPResourceServerRead,
+        Update: resourceCognitoIDPResourceServerUpdate,
+        Delete: resourceCognitoIDPResourceServerDelete,
+        Importer: &schema.ResourceImporter{
+            State: schema.ImportStatePassthrough,
+        },
+
+        CustomizeDiff: resourceCognitoIDPResourceServerCustomizeDiff,
+
+        Schema: map[string]*schema.Schema{
+            "user_pool_id": &schema.Schema{
+                Type: schema.TypeString,
+                Required: true,
+                ForceNew: true,
+            },
+            "identifier": &schema.Schema{
+                Type: schema.TypeString,
+                Required: true,
+                ForceNew: true,
+            },
+            "name": &schema.Schema{
+                Type: schema.TypeString,
+                Required: true,
+            },
+            "scope": &schema.Schema{
+                Type: schema.TypeSet,
+                Optional: true,
+                MaxItems: 100,
+                Elem: &schema.Resource{
+                    Schema: map[string]*schema.Schema{
+                        "scope_name": {
+                            Type: schema.TypeString,
+                            Required: true,
+                        },
+                        "scope_description": {
+                            Type: schema.TypeString,
+                            Required: true,
+                        },
+                    },
+                },
+            },
+            // identifier/scope_name, the form app clients list in
+            // allowed_oauth_scopes
+            "scope_identifiers": &schema.Schema{
+                Type: schema.TypeList,
+                Computed: true,
+                Elem: &schema.Schema{Type: schema.TypeString},
+            },
+        },
+    }
+}
+
+func resourceCognitoIDPResourceServerCreate(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    userPoolId := d.Get("user_pool_id").(string)
+    identifier := d.Get("identifier").(string)
+
+    params := &cognitoidentityprovider.CreateResourceServerInput{
+        UserPoolId: aws.String(userPoolId),
+        Identifier: aws.String(identifier),
+        Name: aws.String(d.Get("name").(string)),
+        Scopes: expandResourceServerScopes(d.Get("scope").(*schema.Set).List()),
+    }
+
+    _, err := cidpconn.CreateResourceServer(params)
+    if err != nil {
+        return fmt.Errorf("Error creating Resource Server %s: %s", identifier, err)
+    }
+
+    d.SetId(fmt.Sprintf("%s/%s", userPoolId, identifier))
+    return resourceCognitoIDPResourceServerRead(d, meta)
+}
+
+func resourceCognitoIDPResourceServerRead(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    id := d.Id()
+
+    // Identifiers are usually URLs, so only the first slash separates them
+    // from the pool ID
+    parts := strings.SplitN(id, "/", 2)
+    if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+        return fmt.Errorf("Wrong format of Resource Server ID (%s), use: 'user-pool-id/identifier'", id)
+    }
+
+    params := &cognitoidentityprovider.DescribeResourceServerInput{
+        UserPoolId: aws.String(parts[0]),
+        Identifier: aws.String(parts[1]),
+    }
+
+    resp, err := cidpconn.DescribeResourceServer(params)
+    if err != nil {
+        if isAWSErr(err, cognitoidentityprovider.ErrCodeResourceNotFoundException, "") {
+            log.Printf("[WARN] Resource Server %s not found, removing from state", id)
+            d.SetId("")
+            return nil
+        }
+        return fmt.Errorf("Error reading Resource Server %s: %s", id, err)
+    }
+
+    rs := resp.ResourceServer
+    d.Set("user_pool_id", rs.UserPoolId)
+    d.Set("identifier", rs.Identifier)
+    d.Set("name", rs.Name)
+
+    scopes := make([]interface{}, 0, len(rs.Scopes))
+    scopeIdentifiers := make([]string, 0, len(rs.Scopes))
+    for _, s := range rs.Scopes {
+        scopes = append(scopes, map[string]interface{}{
+            "scope_name": aws.StringValue(s.ScopeName),
+            "scope_description": aws.StringValue(s.ScopeDescription),
+        })
+        scopeIdentifiers = append(scopeIdentifiers, fmt.Sprintf("%s/%s", aws.StringValue(rs.Identifier), aws.StringValue(s.ScopeName)))
+    }
+
+    if err := d.Set("scope", scopes); err != nil {
+        return fmt.Errorf("Error setting scope for Resource Server %s: %s", id, err)
+    }
+
+    if err := d.Set("scope_identifiers", scopeIdentifiers); err != nil {
+        return fmt.Errorf("Error setting scope_identifiers for Resource Server %s: %s", id, err)
+    }
+
+    return nil
+}
+
+func resourceCognitoIDPResourceServerUpdate(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    id := d.Id()
+
+    params := &cognitoidentityprovider.UpdateResourceServerInput{
+        UserPoolId: aws.String(d.Get("user_pool_id").(string)),
+        Identifier: aws.String(d.Get("identifier").(string)),
+        Name: aws.String(d.Get("name").(string)),
+        Scopes: expandResourceServerScopes(d.Get("scope").(*schema.Set).List()),
+    }
+
+    _, err := cidpconn.UpdateResourceServer(params)
+    if err != nil {
+        return fmt.Errorf("Error updating Resource Server %s: %s", id, err)
+    }
+
+    return resourceCognitoIDPResourceServerRead(d, meta)
+}
+
+func resourceCognitoIDPResourceServerDelete(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    id := d.Id()
+
+    params := &cognitoidentityprovider.DeleteResourceServerInput{
+        UserPoolId: aws.String(d.Get("user_pool_id").(string)),
+        Identifier: aws.String(d.Get("identifier").(string)),
+    }
+
+    _, err := cidpconn.DeleteResourceServer(params)
+    if err != nil && !isAWSErr(err, cognitoidentityprovider.ErrCodeResourceNotFoundException, "") {
+        return fmt.Errorf("Error removing Resource Server %s: %s", id, err)
+    }
+
+    return nil
+}
+
+// Keep clients that reference scope_identifiers from planning against a
+// stale list
+func resourceCognitoIDPResourceServerCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+    if d.HasChange("scope") {
+        return d.SetNewComputed("scope_identifiers")
+    }
+    return nil
+}
+
+func expandResourceServerScopes(l []interface{}) []*cognitoidentityprovider.ResourceServerScopeType {
+    scopes := make([]*cognitoidentityprovider.ResourceServerScopeType, 0, len(l))
+    for _, raw := range l {
+        m := raw.(map[string]interface{})
+        scopes = append(scopes, &cognitoidentityprovider.ResourceServerScopeType{
+            ScopeName: aws.String(m["scope_name"].(string)),
+            ScopeDescription: aws.String(m["scope_description"].(string)),
+        })
+    }
+    return scopes
+}
@@ -0,0 +1,440 @@
+package aws
+
+import (
+    "fmt"
+    "log"
+    "strings"
+
+    "github.com/hashicorp/terraform/helper/schema"
+    "github.com/hashicorp/terraform/helper/validation"
+
+    "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
+)
+
+func resourceTrilityAwsCognitoRiskConfiguration() *schema.Resource {
+    return &schema.Resource{
+        Create: resourceCognitoIDPRiskConfigurationPut,
+        Read: resourceCognitoIDPRiskConfigurationRead,
+        Update: resourceCognitoIDPRiskConfigurationPut,
+        Delete: resourceCognitoIDPRiskConfigurationDelete,
+
+        Schema: map[string]*schema.Schema{
+            "user_pool_id": &schema.Schema{
+                Type: schema.TypeString,
+                Required: true,
+                ForceNew: true,
+            },
+            // Without a client the configuration applies to the whole pool
+            "client_id": &schema.Schema{
+                Type: schema.TypeString,
+                Optional: true,
+                ForceNew: true,
+            },
+            "account_takeover_risk_configuration": &schema.Schema{
+                Type: schema.TypeList,
+                Optional: true,
+                MaxItems: 1,
+                Elem: &schema.Resource{
+                    Schema: map[string]*schema.Schema{
+                        "actions": {
+                            Type: schema.TypeList,
+                            Required: true,
+                            MaxItems: 1,
+                            Elem: &schema.Resource{
+                                Schema: map[string]*schema.Schema{
+                                    "high_action": accountTakeoverActionSchema(),
+                                    "medium_action": accountTakeoverActionSchema(),
+                                    "low_action": accountTakeoverActionSchema(),
+                                },
+                            },
+                        },
+                        "notify_configuration": {
+                            Type: schema.TypeList,
+                            Optional: true,
+                            MaxItems: 1,
+                            Elem: &schema.Resource{
+                                Schema: map[string]*schema.Schema{
+                                    "from": {
+                                        Type: schema.TypeString,
+                                        Optional: true,
+                                    },
+                                    "reply_to": {
+                                        Type: schema.TypeString,
+                                        Optional: true,
+                                    },
+                                    "source_arn": {
+                                        Type: schema.TypeString,
+                                        Required: true,
+                                    },
+                                    "block_email": notifyEmailSchema(),
+                                    "mfa_email": notifyEmailSchema(),
+                                    "no_action_email": notifyEmailSchema(),
+                                },
+                            },
+                        },
+                    },
+                },
+            },
+            "compromised_credentials_risk_configuration": &schema.Schema{
+                Type: schema.TypeList,
+                Optional: true,
+                MaxItems: 1,
+                Elem: &schema.Resource{
+                    Schema: map[string]*schema.Schema{
+                        "event_action": {
+                            Type: schema.TypeString,
+                            Required: true,
+                            ValidateFunc: validation.StringInSlice([]string{
+                                cognitoidentityprovider.CompromisedCredentialsEventActionTypeBlock,
+                                cognitoidentityprovider.CompromisedCredentialsEventActionTypeNoAction,
+                            }, false),
+                        },
+                        "event_filter": {
+                            Type: schema.TypeSet,
+                            Optional: true,
+                            Computed: true,
+                            Elem: &schema.Schema{
+                                Type: schema.TypeString,
+                                ValidateFunc: validation.StringInSlice([]string{
+                                    cognitoidentityprovider.EventFilterTypeSignIn,
+                                    cognitoidentityprovider.EventFilterTypePasswordChange,
+                                    cognitoidentityprovider.EventFilterTypeSignUp,
+                                }, false),
+                            },
+                        },
+                    },
+                },
+            },
+            "risk_exception_configuration": &schema.Schema{
+                Type: schema.TypeList,
+                Optional: true,
+                MaxItems: 1,
+                Elem: &schema.Resource{
+                    Schema: map[string]*schema.Schema{
+                        "blocked_ip_range_list": {
+                            Type: schema.TypeSet,
+                            Optional: true,
+                            MaxItems: 200,
+                            Elem: &schema.Schema{Type: schema.TypeString},
+                        },
+                        "skipped_ip_range_list": {
+                            Type: schema.TypeSet,
+                            Optional: true,
+                            MaxItems: 200,
+                            Elem: &schema.Schema{Type: schema.TypeString},
+                        },
+                    },
+                },
+            },
+        },
+    }
+}
+
+func accountTakeoverActionSchema() *schema.Schema {
+    return &schema.Schema{
+        Type: schema.TypeList,
+        Optional: true,
+        MaxItems: 1,
+        Elem: &schema.Resource{
+            Schema: map[string]*schema.Schema{
+                "event_action": {
+                    Type: schema.TypeString,
+                    Required: true,
+                    ValidateFunc: validation.StringInSlice([]string{
+                        cognitoidentityprovider.AccountTakeoverEventActionTypeBlock,
+                        cognitoidentityprovider.AccountTakeoverEventActionTypeMfaIfConfigured,
+                        cognitoidentityprovider.AccountTakeoverEventActionTypeMfaRequired,
+                        cognitoidentityprovider.AccountTakeoverEventActionTypeNoAction,
+                    }, false),
+                },
+                "notify": {
+                    Type: schema.TypeBool,
+                    Required: true,
+                },
+            },
+        },
+    }
+}
+
+func notifyEmailSchema() *schema.Schema {
+    return &schema.Schema{
+        Type: schema.TypeList,
+        Optional: true,
+        MaxItems: 1,
+        Elem: &schema.Resource{
+            Schema: map[string]*schema.Schema{
+                "html_body": {
+                    Type: schema.TypeString,
+                    Required: true,
+                },
+                "subject": {
+                    Type: schema.TypeString,
+                    Required: true,
+                },
+                "text_body": {
+                    Type: schema.TypeString,
+                    Required: true,
+                },
+            },
+        },
+    }
+}
+
+func resourceCognitoIDPRiskConfigurationPut(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    userPoolId := d.Get("user_pool_id").(string)
+
+    params := &cognitoidentityprovider.SetRiskConfigurationInput{
+        UserPoolId: aws.String(userPoolId),
+    }
+
+    id := userPoolId
+    if v, ok := d.GetOk("client_id"); ok {
+        params.ClientId = aws.String(v.(string))
+        id = fmt.Sprintf("%s/%s", userPoolId, v.(string))
+    }
+
+    if v, ok := d.GetOk("account_takeover_risk_configuration"); ok && v.([]interface{})[0] != nil {
+        params.AccountTakeoverRiskConfiguration = expandAccountTakeoverRiskConfiguration(v.([]interface{})[0].(map[string]interface{}))
+    }
+
+    if v, ok := d.GetOk("compromised_credentials_risk_configuration"); ok && v.([]interface{})[0] != nil {
+        params.CompromisedCredentialsRiskConfiguration = expandCompromisedCredentialsRiskConfiguration(v.([]interface{})[0].(map[string]interface{}))
+    }
+
+    if v, ok := d.GetOk("risk_exception_configuration"); ok && v.([]interface{})[0] != nil {
+        m := v.([]interface{})[0].(map[string]interface{})
+        params.RiskExceptionConfiguration = &cognitoidentityprovider.RiskExceptionConfigurationType{
+            BlockedIPRangeList: expandStringSet(m["blocked_ip_range_list"].(*schema.Set)),
+            SkippedIPRangeList: expandStringSet(m["skipped_ip_range_list"].(*schema.Set)),
+        }
+    }
+
+    _, err := cidpconn.SetRiskConfiguration(params)
+    if err != nil {
+        return fmt.Errorf("Error setting risk configuration %s: %s", id, err)
+    }
+
+    d.SetId(id)
+    return resourceCognitoIDPRiskConfigurationRead(d, meta)
+}
+
+func resourceCognitoIDPRiskConfigurationRead(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    id := d.Id()
+
+    // id is either user_pool_id or user_pool_id/client_id
+    parts := strings.SplitN(id, "/", 2)
+
+    params := &cognitoidentityprovider.DescribeRiskConfigurationInput{
+        UserPoolId: aws.String(parts[0]),
+    }
+    if len(parts) == 2 {
+        params.ClientId = aws.String(parts[1])
+    }
+
+    resp, err := cidpconn.DescribeRiskConfiguration(params)
+    if err != nil {
+        if isAWSErr(err, cognitoidentityprovider.ErrCodeResourceNotFoundException, "") {
+            log.Printf("[WARN] Risk configuration %s not found, removing from state", id)
+            d.SetId("")
+            return nil
+        }
+        return fmt.Errorf("Error reading risk configuration %s: %s", id, err)
+    }
+
+    rc := resp.RiskConfiguration
+    d.Set("user_pool_id", rc.UserPoolId)
+    d.Set("client_id", rc.ClientId)
+
+    if err := d.Set("account_takeover_risk_configuration", flattenAccountTakeoverRiskConfiguration(rc.AccountTakeoverRiskConfiguration)); err != nil {
+        return fmt.Errorf("Error setting account_takeover_risk_configuration for %s: %s", id, err)
+    }
+
+    if err := d.Set("compromised_credentials_risk_configuration", flattenCompromisedCredentialsRiskConfiguration(rc.CompromisedCredentialsRiskConfiguration)); err != nil {
+        return fmt.Errorf("Error setting compromised_credentials_risk_configuration for %s: %s", id, err)
+    }
+
+    var rec []map[string]interface{}
+    if c := rc.RiskExceptionConfiguration; c != nil {
+        rec = []map[string]interface{}{
+            {
+                "blocked_ip_range_list": flattenStringSet(c.BlockedIPRangeList),
+                "skipped_ip_range_list": flattenStringSet(c.SkippedIPRangeList),
+            },
+        }
+    }
+    if err := d.Set("risk_exception_configuration", rec); err != nil {
+        return fmt.Errorf("Error setting risk_exception_configuration for %s: %s", id, err)
+    }
+
+    return nil
+}
+
+func resourceCognitoIDPRiskConfigurationDelete(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    id := d.Id()
+
+    // SetRiskConfiguration without any of the configuration blocks clears
+    // the settings for the pool or client
+    params := &cognitoidentityprovider.SetRiskConfigurationInput{
+        UserPoolId: aws.String(d.Get("user_pool_id").(string)),
+    }
+    if v, ok := d.GetOk("client_id"); ok {
+        params.ClientId = aws.String(v.(string))
+    }
+
+    _, err := cidpconn.SetRiskConfiguration(params)
+    if err != nil && !isAWSErr(err, cognitoidentityprovider.ErrCodeResourceNotFoundException, "") {
+        return fmt.Errorf("Error removing risk configuration %s: %s", id, err)
+    }
+
+    return nil
+}
+
+func expandAccountTakeoverRiskConfiguration(m map[string]interface{}) *cognitoidentityprovider.AccountTakeoverRiskConfigurationType {
+    atrc := &cognitoidentityprovider.AccountTakeoverRiskConfigurationType{
+        Actions: &cognitoidentityprovider.AccountTakeoverActionsType{},
+    }
+
+    if v, ok := m["actions"].([]interface{}); ok && len(v) > 0 && v[0] != nil {
+        a := v[0].(map[string]interface{})
+        atrc.Actions.HighAction = expandAccountTakeoverAction(a["high_action"].([]interface{}))
+        atrc.Actions.MediumAction = expandAccountTakeoverAction(a["medium_action"].([]interface{}))
+        atrc.Actions.LowAction = expandAccountTakeoverAction(a["low_action"].([]interface{}))
+    }
+
+    if v, ok := m["notify_configuration"].([]interface{}); ok && len(v) > 0 && v[0] != nil {
+        n := v[0].(map[string]interface{})
+        nc := &cognitoidentityprovider.NotifyConfigurationType{
+            SourceArn: aws.String(n["source_arn"].(string)),
+            BlockEmail: expandNotifyEmail(n["block_email"].([]interface{})),
+            MfaEmail: expandNotifyEmail(n["mfa_email"].([]interface{})),
+            NoActionEmail: expandNotifyEmail(n["no_action_email"].([]interface{})),
+        }
+        if v, ok := n["from"].(string); ok && v != "" {
+            nc.From = aws.String(v)
+        }
+        if v, ok := n["reply_to"].(string); ok && v != "" {
+            nc.ReplyTo = aws.String(v)
+        }
+        atrc.NotifyConfiguration = nc
+    }
+
+    return atrc
+}
+
+func expandAccountTakeoverAction(l []interface{}) *cognitoidentityprovider.AccountTakeoverActionType {
+    if len(l) == 0 || l[0] == nil {
+        return nil
+    }
+
+    m := l[0].(map[string]interface{})
+    return &cognitoidentityprovider.AccountTakeoverActionType{
+        EventAction: aws.String(m["event_action"].(string)),
+        Notify: aws.Bool(m["notify"].(bool)),
+    }
+}
+
+func expandNotifyEmail(l []interface{}) *cognitoidentityprovider.NotifyEmailType {
+    if len(l) == 0 || l[0] == nil {
+        return nil
+    }
+
+    m := l[0].(map[string]interface{})
+    return &cognitoidentityprovider.NotifyEmailType{
+        HtmlBody: aws.String(m["html_body"].(string)),
+        Subject: aws.String(m["subject"].(string)),
+        TextBody: aws.String(m["text_body"].(string)),
+    }
+}
+
+func flattenAccountTakeoverRiskConfiguration(atrc *cognitoidentityprovider.AccountTakeoverRiskConfigurationType) []map[string]interface{} {
+    if atrc == nil {
+        return nil
+    }
+
+    m := map[string]interface{}{}
+
+    if a := atrc.Actions; a != nil {
+        m["actions"] = []map[string]interface{}{
+            {
+                "high_action": flattenAccountTakeoverAction(a.HighAction),
+                "medium_action": flattenAccountTakeoverAction(a.MediumAction),
+                "low_action": flattenAccountTakeoverAction(a.LowAction),
+            },
+        }
+    }
+
+    if nc := atrc.NotifyConfiguration; nc != nil {
+        m["notify_configuration"] = []map[string]interface{}{
+            {
+                "from": aws.StringValue(nc.From),
+                "reply_to": aws.StringValue(nc.ReplyTo),
+                "source_arn": aws.StringValue(nc.SourceArn),
+                "block_email": flattenNotifyEmail(nc.BlockEmail),
+                "mfa_email": flattenNotifyEmail(nc.MfaEmail),
+                "no_action_email": flattenNotifyEmail(nc.NoActionEmail),
+            },
+        }
+    }
+
+    return []map[string]interface{}{m}
+}
+
+func flattenAccountTakeoverAction(a *cognitoidentityprovider.AccountTakeoverActionType) []map[string]interface{} {
+    if a == nil {
+        return nil
+    }
+
+    return []map[string]interface{}{
+        {
+            "event_action": aws.StringValue(a.EventAction),
+            "notify": aws.BoolValue(a.Notify),
+        },
+    }
+}
+
+func flattenNotifyEmail(e *cognitoidentityprovider.NotifyEmailType) []map[string]interface{} {
+    if e == nil {
+        return nil
+    }
+
+    return []map[string]interface{}{
+        {
+            "html_body": aws.StringValue(e.HtmlBody),
+            "subject": aws.StringValue(e.Subject),
+            "text_body": aws.StringValue(e.TextBody),
+        },
+    }
+}
+
+func expandCompromisedCredentialsRiskConfiguration(m map[string]interface{}) *cognitoidentityprovider.CompromisedCredentialsRiskConfigurationType {
+    ccrc := &cognitoidentityprovider.CompromisedCredentialsRiskConfigurationType{
+        Actions: &cognitoidentityprovider.CompromisedCredentialsActionsType{
+            EventAction: aws.String(m["event_action"].(string)),
+        },
+    }
+
+    if v, ok := m["event_filter"].(*schema.Set); ok && v.Len() > 0 {
+        ccrc.EventFilter = expandStringSet(v)
+    }
+
+    return ccrc
+}
+
+func flattenCompromisedCredentialsRiskConfiguration(ccrc *cognitoidentityprovider.CompromisedCredentialsRiskConfigurationType) []map[string]interface{} {
+    if ccrc == nil {
+        return nil
+    }
+
+    m := map[string]interface{}{
+        "event_filter": flattenStringSet(ccrc.EventFilter),
+    }
+    if ccrc.Actions != nil {
+        m["event_action"] = aws.StringValue(ccrc.Actions.EventAction)
+    }
+
+    return []map[string]interface{}{m}
+}
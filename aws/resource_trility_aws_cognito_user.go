@@ -0,0 +1,354 @@
+package aws
+
+import (
+    "fmt"
+    "log"
+    "strings"
+    "time"
+
+    "github.com/hashicorp/terraform/helper/schema"
+    "github.com/hashicorp/terraform/helper/validation"
+
+    "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
+)
+
+func resourceTrilityAwsCognitoUser() *schema.Resource {
+    return &schema.Resource{
+        Create: resourceCognitoIDPUserCreate,
+        Read: resourceCognitoIDPUserRead,
+        Update: resourceCognitoIDPUserUpdate,
+        Delete: resourceCognitoIDPUserDelete,
+        Importer: &schema.ResourceImporter{
+            State: schema.ImportStatePassthrough,
+        },
+
+        Schema: map[string]*schema.Schema{
+            "user_pool_id": &schema.Schema{
+                Type: schema.TypeString,
+                Required: true,
+                ForceNew: true,
+            },
+            "username": &schema.Schema{
+                Type: schema.TypeString,
+                Required: true,
+                ForceNew: true,
+            },
+            "attributes": &schema.Schema{
+                Type: schema.TypeMap,
+                Optional: true,
+                Elem: &schema.Schema{Type: schema.TypeString},
+            },
+            "message_action": &schema.Schema{
+                Type: schema.TypeString,
+                Optional: true,
+                ValidateFunc: validation.StringInSlice([]string{
+                    cognitoidentityprovider.MessageActionTypeResend,
+                    cognitoidentityprovider.MessageActionTypeSuppress,
+                }, false),
+            },
+            "desired_delivery_mediums": &schema.Schema{
+                Type: schema.TypeSet,
+                Optional: true,
+                Elem: &schema.Schema{
+                    Type: schema.TypeString,
+                    ValidateFunc: validation.StringInSlice([]string{
+                        cognitoidentityprovider.DeliveryMediumTypeEmail,
+                        cognitoidentityprovider.DeliveryMediumTypeSms,
+                    }, false),
+                },
+            },
+            "force_alias_creation": &schema.Schema{
+                Type: schema.TypeBool,
+                Optional: true,
+                Default: false,
+            },
+            // The user has to replace a temporary password on first sign-in
+            "temporary_password": &schema.Schema{
+                Type: schema.TypeString,
+                Optional: true,
+                Sensitive: true,
+                ConflictsWith: []string{"password"},
+            },
+            "password": &schema.Schema{
+                Type: schema.TypeString,
+                Optional: true,
+                Sensitive: true,
+                ConflictsWith: []string{"temporary_password"},
+            },
+            "enabled": &schema.Schema{
+                Type: schema.TypeBool,
+                Optional: true,
+                Default: true,
+            },
+            "status": &schema.Schema{
+                Type: schema.TypeString,
+                Computed: true,
+            },
+            "sub": &schema.Schema{
+                Type: schema.TypeString,
+                Computed: true,
+            },
+            "creation_date": &schema.Schema{
+                Type: schema.TypeString,
+                Computed: true,
+            },
+            "last_modified_date": &schema.Schema{
+                Type: schema.TypeString,
+                Computed: true,
+            },
+        },
+    }
+}
+
+func resourceCognitoIDPUserCreate(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    userPoolId := d.Get("user_pool_id").(string)
+    username := d.Get("username").(string)
+
+    params := &cognitoidentityprovider.AdminCreateUserInput{
+        UserPoolId: aws.String(userPoolId),
+        Username: aws.String(username),
+        ForceAliasCreation: aws.Bool(d.Get("force_alias_creation").(bool)),
+    }
+
+    if v, ok := d.GetOk("attributes"); ok {
+        params.UserAttributes = expandCognitoIDPUserAttributes(v.(map[string]interface{}))
+    }
+
+    if v, ok := d.GetOk("message_action"); ok {
+        params.MessageAction = aws.String(v.(string))
+    }
+
+    if v, ok := d.GetOk("desired_delivery_mediums"); ok {
+        params.DesiredDeliveryMediums = expandStringSet(v.(*schema.Set))
+    }
+
+    if v, ok := d.GetOk("temporary_password"); ok {
+        params.TemporaryPassword = aws.String(v.(string))
+    }
+
+    _, err := cidpconn.AdminCreateUser(params)
+    if err != nil {
+        return fmt.Errorf("Error creating User %s: %s", username, err)
+    }
+
+    d.SetId(fmt.Sprintf("%s/%s", userPoolId, username))
+
+    if v, ok := d.GetOk("password"); ok {
+        if err := setCognitoIDPUserPassword(cidpconn, userPoolId, username, v.(string), true); err != nil {
+            return err
+        }
+    }
+
+    if !d.Get("enabled").(bool) {
+        if err := setCognitoIDPUserEnabled(cidpconn, userPoolId, username, false); err != nil {
+            return err
+        }
+    }
+
+    return resourceCognitoIDPUserRead(d, meta)
+}
+
+func resourceCognitoIDPUserRead(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    id := d.Id()
+
+    userPoolId, username, err := decodeCognitoIDPUserId(id)
+    if err != nil {
+        return err
+    }
+
+    params := &cognitoidentityprovider.AdminGetUserInput{
+        UserPoolId: aws.String(userPoolId),
+        Username: aws.String(username),
+    }
+
+    resp, err := cidpconn.AdminGetUser(params)
+    if err != nil {
+        if isAWSErr(err, cognitoidentityprovider.ErrCodeUserNotFoundException, "") {
+            log.Printf("[WARN] User %s not found, removing from state", id)
+            d.SetId("")
+            return nil
+        }
+        return fmt.Errorf("Error reading User %s: %s", id, err)
+    }
+
+    // Pools with username_attributes answer with the sub as Username, so
+    // keep the name the user was created with
+    d.Set("user_pool_id", userPoolId)
+    d.Set("username", username)
+    d.Set("enabled", resp.Enabled)
+    d.Set("status", resp.UserStatus)
+    if resp.UserCreateDate != nil {
+        d.Set("creation_date", resp.UserCreateDate.Format(time.RFC3339))
+    }
+    if resp.UserLastModifiedDate != nil {
+        d.Set("last_modified_date", resp.UserLastModifiedDate.Format(time.RFC3339))
+    }
+
+    configured := d.Get("attributes").(map[string]interface{})
+    attributes := make(map[string]interface{})
+    for _, a := range resp.UserAttributes {
+        name := aws.StringValue(a.Name)
+        if name == "sub" {
+            d.Set("sub", a.Value)
+            continue
+        }
+        // Cognito sets the verified flags itself as users confirm their
+        // email and phone number
+        if _, ok := configured[name]; !ok && strings.HasSuffix(name, "_verified") {
+            continue
+        }
+        attributes[name] = aws.StringValue(a.Value)
+    }
+    if err := d.Set("attributes", attributes); err != nil {
+        return fmt.Errorf("Error setting attributes for User %s: %s", id, err)
+    }
+
+    return nil
+}
+
+func resourceCognitoIDPUserUpdate(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    id := d.Id()
+    userPoolId := d.Get("user_pool_id").(string)
+    username := d.Get("username").(string)
+
+    if d.HasChange("attributes") {
+        o, n := d.GetChange("attributes")
+        om := o.(map[string]interface{})
+        nm := n.(map[string]interface{})
+
+        updated := make(map[string]interface{})
+        for k, v := range nm {
+            if ov, ok := om[k]; !ok || ov != v {
+                updated[k] = v
+            }
+        }
+
+        removed := make([]*string, 0)
+        for k := range om {
+            if _, ok := nm[k]; !ok {
+                removed = append(removed, aws.String(k))
+            }
+        }
+
+        if len(updated) > 0 {
+            _, err := cidpconn.AdminUpdateUserAttributes(&cognitoidentityprovider.AdminUpdateUserAttributesInput{
+                UserPoolId: aws.String(userPoolId),
+                Username: aws.String(username),
+                UserAttributes: expandCognitoIDPUserAttributes(updated),
+            })
+            if err != nil {
+                return fmt.Errorf("Error updating attributes of User %s: %s", id, err)
+            }
+        }
+
+        if len(removed) > 0 {
+            _, err := cidpconn.AdminDeleteUserAttributes(&cognitoidentityprovider.AdminDeleteUserAttributesInput{
+                UserPoolId: aws.String(userPoolId),
+                Username: aws.String(username),
+                UserAttributeNames: removed,
+            })
+            if err != nil {
+                return fmt.Errorf("Error removing attributes of User %s: %s", id, err)
+            }
+        }
+    }
+
+    if d.HasChange("temporary_password") {
+        if v, ok := d.GetOk("temporary_password"); ok {
+            if err := setCognitoIDPUserPassword(cidpconn, userPoolId, username, v.(string), false); err != nil {
+                return err
+            }
+        }
+    }
+
+    if d.HasChange("password") {
+        if v, ok := d.GetOk("password"); ok {
+            if err := setCognitoIDPUserPassword(cidpconn, userPoolId, username, v.(string), true); err != nil {
+                return err
+            }
+        }
+    }
+
+    if d.HasChange("enabled") {
+        if err := setCognitoIDPUserEnabled(cidpconn, userPoolId, username, d.Get("enabled").(bool)); err != nil {
+            return err
+        }
+    }
+
+    return resourceCognitoIDPUserRead(d, meta)
+}
+
+func resourceCognitoIDPUserDelete(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    id := d.Id()
+
+    params := &cognitoidentityprovider.AdminDeleteUserInput{
+        UserPoolId: aws.String(d.Get("user_pool_id").(string)),
+        Username: aws.String(d.Get("username").(string)),
+    }
+
+    _, err := cidpconn.AdminDeleteUser(params)
+    if err != nil && !isAWSErr(err, cognitoidentityprovider.ErrCodeUserNotFoundException, "") {
+        return fmt.Errorf("Error removing User %s: %s", id, err)
+    }
+
+    return nil
+}
+
+func setCognitoIDPUserPassword(cidpconn *cognitoidentityprovider.CognitoIdentityProvider, userPoolId, username, password string, permanent bool) error {
+    _, err := cidpconn.AdminSetUserPassword(&cognitoidentityprovider.AdminSetUserPasswordInput{
+        UserPoolId: aws.String(userPoolId),
+        Username: aws.String(username),
+        Password: aws.String(password),
+        Permanent: aws.Bool(permanent),
+    })
+    if err != nil {
+        return fmt.Errorf("Error setting password of User %s/%s: %s", userPoolId, username, err)
+    }
+
+    return nil
+}
+
+func setCognitoIDPUserEnabled(cidpconn *cognitoidentityprovider.CognitoIdentityProvider, userPoolId, username string, enabled bool) error {
+    var err error
+    if enabled {
+        _, err = cidpconn.AdminEnableUser(&cognitoidentityprovider.AdminEnableUserInput{
+            UserPoolId: aws.String(userPoolId),
+            Username: aws.String(username),
+        })
+    } else {
+        _, err = cidpconn.AdminDisableUser(&cognitoidentityprovider.AdminDisableUserInput{
+            UserPoolId: aws.String(userPoolId),
+            Username: aws.String(username),
+        })
+    }
+    if err != nil {
+        return fmt.Errorf("Error changing status of User %s/%s: %s", userPoolId, username, err)
+    }
+
+    return nil
+}
+
+func expandCognitoIDPUserAttributes(m map[string]interface{}) []*cognitoidentityprovider.AttributeType {
+    attributes := make([]*cognitoidentityprovider.AttributeType, 0, len(m))
+    for k, v := range m {
+        attributes = append(attributes, &cognitoidentityprovider.AttributeType{
+            Name: aws.String(k),
+            Value: aws.String(v.(string)),
+        })
+    }
+    return attributes
+}
+
+// The ID, and the import ID, is user_pool_id/username
+func decodeCognitoIDPUserId(id string) (string, string, error) {
+    parts := strings.SplitN(id, "/", 2)
+    if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+        return "", "", fmt.Errorf("Wrong format of User ID (%s), use: 'user-pool-id/username'", id)
+    }
+    return parts[0], parts[1], nil
+}
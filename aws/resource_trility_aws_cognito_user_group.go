@@ -0,0 +1,176 @@
+package aws
+
+import (
+    "fmt"
+    "log"
+    "strings"
+
+    "github.com/hashicorp/terraform/helper/schema"
+    "github.com/hashicorp/terraform/helper/validation"
+
+    "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
+)
+
+func resourceTrilityAwsCognitoUserGroup() *schema.Resource {
+    return &schema.Resource{
+        Create: resourceCognitoIDPUserGroupCreate,
+        Read: resourceCognitoIDPUserGroupRead,
+        Update: resourceCognitoIDPUserGroupUpdate,
+        Delete: resourceCognitoIDPUserGroupDelete,
+        Importer: &schema.ResourceImporter{
+            State: schema.ImportStatePassthrough,
+        },
+
+        Schema: map[string]*schema.Schema{
+            "user_pool_id": &schema.Schema{
+                Type: schema.TypeString,
+                Required: true,
+                ForceNew: true,
+            },
+            "name": &schema.Schema{
+                Type: schema.TypeString,
+                Required: true,
+                ForceNew: true,
+            },
+            "description": &schema.Schema{
+                Type: schema.TypeString,
+                Optional: true,
+                ValidateFunc: validation.StringLenBetween(0, 2048),
+            },
+            // Lower values win when a user's groups map to different roles
+            "precedence": &schema.Schema{
+                Type: schema.TypeInt,
+                Optional: true,
+                ValidateFunc: validation.IntAtLeast(0),
+            },
+            "role_arn": &schema.Schema{
+                Type: schema.TypeString,
+                Optional: true,
+            },
+        },
+    }
+}
+
+func resourceCognitoIDPUserGroupCreate(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    userPoolId := d.Get("user_pool_id").(string)
+    name := d.Get("name").(string)
+
+    params := &cognitoidentityprovider.CreateGroupInput{
+        UserPoolId: aws.String(userPoolId),
+        GroupName: aws.String(name),
+    }
+
+    if v, ok := d.GetOk("description"); ok {
+        params.Description = aws.String(v.(string))
+    }
+
+    // 0 is the highest precedence, so it counts as set
+    if v, ok := d.GetOkExists("precedence"); ok {
+        params.Precedence = aws.Int64(int64(v.(int)))
+    }
+
+    if v, ok := d.GetOk("role_arn"); ok {
+        params.RoleArn = aws.String(v.(string))
+    }
+
+    _, err := cidpconn.CreateGroup(params)
+    if err != nil {
+        return fmt.Errorf("Error creating User Group %s: %s", name, err)
+    }
+
+    d.SetId(fmt.Sprintf("%s/%s", userPoolId, name))
+    return resourceCognitoIDPUserGroupRead(d, meta)
+}
+
+func resourceCognitoIDPUserGroupRead(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    id := d.Id()
+
+    userPoolId, name, err := decodeCognitoIDPUserGroupId(id)
+    if err != nil {
+        return err
+    }
+
+    params := &cognitoidentityprovider.GetGroupInput{
+        UserPoolId: aws.String(userPoolId),
+        GroupName: aws.String(name),
+    }
+
+    resp, err := cidpconn.GetGroup(params)
+    if err != nil {
+        if isAWSErr(err, cognitoidentityprovider.ErrCodeResourceNotFoundException, "") {
+            log.Printf("[WARN] User Group %s not found, removing from state", id)
+            d.SetId("")
+            return nil
+        }
+        return fmt.Errorf("Error reading User Group %s: %s", id, err)
+    }
+
+    group := resp.Group
+    d.Set("user_pool_id", group.UserPoolId)
+    d.Set("name", group.GroupName)
+    d.Set("description", group.Description)
+    d.Set("precedence", group.Precedence)
+    d.Set("role_arn", group.RoleArn)
+
+    return nil
+}
+
+func resourceCognitoIDPUserGroupUpdate(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    id := d.Id()
+
+    params := &cognitoidentityprovider.UpdateGroupInput{
+        UserPoolId: aws.String(d.Get("user_pool_id").(string)),
+        GroupName: aws.String(d.Get("name").(string)),
+    }
+
+    // UpdateGroup clears anything left out of the request
+    if v, ok := d.GetOk("description"); ok {
+        params.Description = aws.String(v.(string))
+    }
+
+    // 0 is the highest precedence, so it counts as set
+    if v, ok := d.GetOkExists("precedence"); ok {
+        params.Precedence = aws.Int64(int64(v.(int)))
+    }
+
+    if v, ok := d.GetOk("role_arn"); ok {
+        params.RoleArn = aws.String(v.(string))
+    }
+
+    _, err := cidpconn.UpdateGroup(params)
+    if err != nil {
+        return fmt.Errorf("Error updating User Group %s: %s", id, err)
+    }
+
+    return resourceCognitoIDPUserGroupRead(d, meta)
+}
+
+func resourceCognitoIDPUserGroupDelete(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    id := d.Id()
+
+    params := &cognitoidentityprovider.DeleteGroupInput{
+        UserPoolId: aws.String(d.Get("user_pool_id").(string)),
+        GroupName: aws.String(d.Get("name").(string)),
+    }
+
+    _, err := cidpconn.DeleteGroup(params)
+    if err != nil && !isAWSErr(err, cognitoidentityprovider.ErrCodeResourceNotFoundException, "") {
+        return fmt.Errorf("Error removing User Group %s: %s", id, err)
+    }
+
+    return nil
+}
+
+// The ID, and the import ID, is user_pool_id/group_name
+func decodeCognitoIDPUserGroupId(id string) (string, string, error) {
+    parts := strings.SplitN(id, "/", 2)
+    if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+        return "", "", fmt.Errorf("Wrong format of User Group ID (%s), use: 'user-pool-id/group-name'", id)
+    }
+    return parts[0], parts[1], nil
+}
@@ -0,0 +1,226 @@
+package aws
+
+import (
+    "fmt"
+    "log"
+
+    "github.com/hashicorp/terraform/helper/schema"
+
+    "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
+)
+
+func resourceTrilityAwsCognitoUserGroupMembership() *schema.Resource {
+    return &schema.Resource{
+        Create: resourceCognitoIDPUserGroupMembershipCreate,
+        Read: resourceCognitoIDPUserGroupMembershipRead,
+        Update: resourceCognitoIDPUserGroupMembershipUpdate,
+        Delete: resourceCognitoIDPUserGroupMembershipDelete,
+
+        Schema: map[string]*schema.Schema{
+            "user_pool_id": &schema.Schema{
+                Type: schema.TypeString,
+                Required: true,
+                ForceNew: true,
+            },
+            "group_name": &schema.Schema{
+                Type: schema.TypeString,
+                Required: true,
+                ForceNew: true,
+            },
+            "users": &schema.Schema{
+                Type: schema.TypeSet,
+                Required: true,
+                Elem: &schema.Schema{Type: schema.TypeString},
+            },
+            // By default this resource owns the whole member list and removes
+            // anyone added outside Terraform. With exclusive set to false it
+            // only makes sure the listed users are members.
+            "exclusive": &schema.Schema{
+                Type: schema.TypeBool,
+                Optional: true,
+                Default: true,
+            },
+        },
+    }
+}
+
+func resourceCognitoIDPUserGroupMembershipCreate(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    userPoolId := d.Get("user_pool_id").(string)
+    groupName := d.Get("group_name").(string)
+
+    d.SetId(fmt.Sprintf("%s/%s", userPoolId, groupName))
+
+    add := d.Get("users").(*schema.Set)
+    if d.Get("exclusive").(bool) {
+        members, err := listCognitoIDPUsersInGroup(cidpconn, userPoolId, groupName, add)
+        if err != nil {
+            return fmt.Errorf("Error listing users in User Group %s: %s", d.Id(), err)
+        }
+
+        current := schema.NewSet(schema.HashString, members)
+        if err := removeCognitoIDPUsersFromGroup(cidpconn, userPoolId, groupName, current.Difference(add).List()); err != nil {
+            return err
+        }
+        add = add.Difference(current)
+    }
+
+    if err := addCognitoIDPUsersToGroup(cidpconn, userPoolId, groupName, add.List()); err != nil {
+        return err
+    }
+
+    return resourceCognitoIDPUserGroupMembershipRead(d, meta)
+}
+
+func resourceCognitoIDPUserGroupMembershipRead(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    id := d.Id()
+    userPoolId := d.Get("user_pool_id").(string)
+    groupName := d.Get("group_name").(string)
+
+    configured := d.Get("users").(*schema.Set)
+    members, err := listCognitoIDPUsersInGroup(cidpconn, userPoolId, groupName, configured)
+    if err != nil {
+        if isAWSErr(err, cognitoidentityprovider.ErrCodeResourceNotFoundException, "") {
+            log.Printf("[WARN] User Group %s not found, removing membership from state", id)
+            d.SetId("")
+            return nil
+        }
+        return fmt.Errorf("Error listing users in User Group %s: %s", id, err)
+    }
+
+    // In non-exclusive mode, members managed elsewhere are none of our
+    // business and must not show up as drift
+    if !d.Get("exclusive").(bool) {
+        owned := make([]interface{}, 0, len(members))
+        for _, m := range members {
+            if configured.Contains(m) {
+                owned = append(owned, m)
+            }
+        }
+        members = owned
+    }
+
+    if err := d.Set("users", schema.NewSet(schema.HashString, members)); err != nil {
+        return fmt.Errorf("Error setting users for User Group %s: %s", id, err)
+    }
+
+    return nil
+}
+
+func resourceCognitoIDPUserGroupMembershipUpdate(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    userPoolId := d.Get("user_pool_id").(string)
+    groupName := d.Get("group_name").(string)
+
+    if d.HasChange("users") {
+        o, n := d.GetChange("users")
+        os := o.(*schema.Set)
+        ns := n.(*schema.Set)
+
+        if err := removeCognitoIDPUsersFromGroup(cidpconn, userPoolId, groupName, os.Difference(ns).List()); err != nil {
+            return err
+        }
+
+        if err := addCognitoIDPUsersToGroup(cidpconn, userPoolId, groupName, ns.Difference(os).List()); err != nil {
+            return err
+        }
+    }
+
+    // Members added elsewhere were left alone until now, and Read only shows
+    // them after this apply, so they are removed here as Create would
+    if d.HasChange("exclusive") && d.Get("exclusive").(bool) {
+        configured := d.Get("users").(*schema.Set)
+        members, err := listCognitoIDPUsersInGroup(cidpconn, userPoolId, groupName, configured)
+        if err != nil {
+            return fmt.Errorf("Error listing users in User Group %s: %s", d.Id(), err)
+        }
+
+        current := schema.NewSet(schema.HashString, members)
+        if err := removeCognitoIDPUsersFromGroup(cidpconn, userPoolId, groupName, current.Difference(configured).List()); err != nil {
+            return err
+        }
+    }
+
+    return resourceCognitoIDPUserGroupMembershipRead(d, meta)
+}
+
+func resourceCognitoIDPUserGroupMembershipDelete(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+
+    return removeCognitoIDPUsersFromGroup(cidpconn, d.Get("user_pool_id").(string), d.Get("group_name").(string), d.Get("users").(*schema.Set).List())
+}
+
+// listCognitoIDPUsersInGroup returns every member under the name it is
+// configured with. Pools with username_attributes report the sub as
+// Username, so a member is also recognised by its sub, email or phone number.
+func listCognitoIDPUsersInGroup(cidpconn *cognitoidentityprovider.CognitoIdentityProvider, userPoolId, groupName string, configured *schema.Set) ([]interface{}, error) {
+    params := &cognitoidentityprovider.ListUsersInGroupInput{
+        UserPoolId: aws.String(userPoolId),
+        GroupName: aws.String(groupName),
+    }
+
+    members := make([]interface{}, 0)
+    err := cidpconn.ListUsersInGroupPages(params, func(page *cognitoidentityprovider.ListUsersInGroupOutput, lastPage bool) bool {
+        for _, u := range page.Users {
+            members = append(members, cognitoIDPGroupMemberName(u, configured))
+        }
+        return !lastPage
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    return members, nil
+}
+
+func addCognitoIDPUsersToGroup(cidpconn *cognitoidentityprovider.CognitoIdentityProvider, userPoolId, groupName string, users []interface{}) error {
+    for _, u := range users {
+        _, err := cidpconn.AdminAddUserToGroup(&cognitoidentityprovider.AdminAddUserToGroupInput{
+            UserPoolId: aws.String(userPoolId),
+            GroupName: aws.String(groupName),
+            Username: aws.String(u.(string)),
+        })
+        if err != nil {
+            return fmt.Errorf("Error adding user %s to User Group %s/%s: %s", u, userPoolId, groupName, err)
+        }
+    }
+    return nil
+}
+
+func removeCognitoIDPUsersFromGroup(cidpconn *cognitoidentityprovider.CognitoIdentityProvider, userPoolId, groupName string, users []interface{}) error {
+    for _, u := range users {
+        _, err := cidpconn.AdminRemoveUserFromGroup(&cognitoidentityprovider.AdminRemoveUserFromGroupInput{
+            UserPoolId: aws.String(userPoolId),
+            GroupName: aws.String(groupName),
+            Username: aws.String(u.(string)),
+        })
+        // A user or group deleted in the meantime leaves nothing to remove
+        if isAWSErr(err, cognitoidentityprovider.ErrCodeResourceNotFoundException, "") {
+            return nil
+        }
+        if err != nil && !isAWSErr(err, cognitoidentityprovider.ErrCodeUserNotFoundException, "") {
+            return fmt.Errorf("Error removing user %s from User Group %s/%s: %s", u, userPoolId, groupName, err)
+        }
+    }
+    return nil
+}
+
+func cognitoIDPGroupMemberName(u *cognitoidentityprovider.UserType, configured *schema.Set) string {
+    username := aws.StringValue(u.Username)
+    if configured.Contains(username) {
+        return username
+    }
+
+    for _, a := range u.Attributes {
+        switch aws.StringValue(a.Name) {
+        case "sub", "email", "phone_number":
+            if v := aws.StringValue(a.Value); configured.Contains(v) {
+                return v
+            }
+        }
+    }
+
+    return username
+}
@@ -0,0 +1,325 @@
+package aws
+
+import (
+    "bytes"
+    "encoding/csv"
+    "fmt"
+    "io"
+    "io/ioutil"
+    "log"
+    "net/http"
+    "os"
+    "strings"
+    "time"
+
+    "github.com/hashicorp/go-cleanhttp"
+    "github.com/hashicorp/terraform/helper/resource"
+    "github.com/hashicorp/terraform/helper/schema"
+
+    "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
+)
+
+// An import job runs once. Every argument is ForceNew, so changing the CSV
+// (tracked through csv_hash) starts a new job.
+func resourceTrilityAwsCognitoUserImportJob() *schema.Resource {
+    return &schema.Resource{
+        Create: resourceCognitoIDPUserImportJobCreate,
+        Read: resourceCognitoIDPUserImportJobRead,
+        Delete: resourceCognitoIDPUserImportJobDelete,
+
+        CustomizeDiff: resourceCognitoIDPUserImportJobCustomizeDiff,
+
+        Timeouts: &schema.ResourceTimeout{
+            Create: schema.DefaultTimeout(60 * time.Minute),
+        },
+
+        Schema: map[string]*schema.Schema{
+            "user_pool_id": &schema.Schema{
+                Type: schema.TypeString,
+                Required: true,
+                ForceNew: true,
+            },
+            "job_name": &schema.Schema{
+                Type: schema.TypeString,
+                Required: true,
+                ForceNew: true,
+            },
+            // Role that lets Cognito write the job's logs to CloudWatch
+            "cloudwatch_logs_role_arn": &schema.Schema{
+                Type: schema.TypeString,
+                Required: true,
+                ForceNew: true,
+            },
+            "csv_file": &schema.Schema{
+                Type: schema.TypeString,
+                Required: true,
+                ForceNew: true,
+            },
+            "csv_hash": &schema.Schema{
+                Type: schema.TypeString,
+                Computed: true,
+            },
+            "status": &schema.Schema{
+                Type: schema.TypeString,
+                Computed: true,
+            },
+            "completion_message": &schema.Schema{
+                Type: schema.TypeString,
+                Computed: true,
+            },
+            "imported_users": &schema.Schema{
+                Type: schema.TypeInt,
+                Computed: true,
+            },
+            "skipped_users": &schema.Schema{
+                Type: schema.TypeInt,
+                Computed: true,
+            },
+            "failed_users": &schema.Schema{
+                Type: schema.TypeInt,
+                Computed: true,
+            },
+        },
+    }
+}
+
+func resourceCognitoIDPUserImportJobCreate(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    userPoolId := d.Get("user_pool_id").(string)
+    jobName := d.Get("job_name").(string)
+    csvFile := d.Get("csv_file").(string)
+
+    content, err := ioutil.ReadFile(csvFile)
+    if err != nil {
+        return fmt.Errorf("Error reading csv_file %s: %s", csvFile, err)
+    }
+
+    header, err := cidpconn.GetCSVHeader(&cognitoidentityprovider.GetCSVHeaderInput{
+        UserPoolId: aws.String(userPoolId),
+    })
+    if err != nil {
+        return fmt.Errorf("Error fetching CSV header for User Pool %s: %s", userPoolId, err)
+    }
+
+    if err := validateCognitoIDPUserImportCSV(content, aws.StringValueSlice(header.CSVHeader)); err != nil {
+        return fmt.Errorf("csv_file %s does not match the header of User Pool %s: %s", csvFile, userPoolId, err)
+    }
+
+    resp, err := cidpconn.CreateUserImportJob(&cognitoidentityprovider.CreateUserImportJobInput{
+        UserPoolId: aws.String(userPoolId),
+        JobName: aws.String(jobName),
+        CloudWatchLogsRoleArn: aws.String(d.Get("cloudwatch_logs_role_arn").(string)),
+    })
+    if err != nil {
+        return fmt.Errorf("Error creating User Import Job %s: %s", jobName, err)
+    }
+
+    job := resp.UserImportJob
+    jobId := *job.JobId
+    d.SetId(jobId)
+    d.Set("csv_hash", sha256Hex(content))
+
+    log.Printf("[DEBUG] Uploading %s for User Import Job %s", csvFile, jobId)
+    if err := uploadCognitoIDPUserImportCSV(aws.StringValue(job.PreSignedUrl), content); err != nil {
+        return fmt.Errorf("Error uploading csv_file for User Import Job %s: %s", jobId, err)
+    }
+
+    _, err = cidpconn.StartUserImportJob(&cognitoidentityprovider.StartUserImportJobInput{
+        UserPoolId: aws.String(userPoolId),
+        JobId: aws.String(jobId),
+    })
+    if err != nil {
+        return fmt.Errorf("Error starting User Import Job %s: %s", jobId, err)
+    }
+
+    stateConf := &resource.StateChangeConf{
+        Pending: []string{
+            cognitoidentityprovider.UserImportJobStatusTypeCreated,
+            cognitoidentityprovider.UserImportJobStatusTypePending,
+            cognitoidentityprovider.UserImportJobStatusTypeInProgress,
+        },
+        Target: []string{
+            cognitoidentityprovider.UserImportJobStatusTypeSucceeded,
+        },
+        Refresh: cognitoIDPUserImportJobStateRefreshFunc(cidpconn, userPoolId, jobId),
+        Timeout: d.Timeout(schema.TimeoutCreate),
+        MinTimeout: 5 * time.Second,
+    }
+
+    _, err = stateConf.WaitForState()
+    if err != nil {
+        // Keep the counts and completion message around for a failed job
+        if rerr := resourceCognitoIDPUserImportJobRead(d, meta); rerr != nil {
+            log.Printf("[WARN] %s", rerr)
+        }
+        return fmt.Errorf("Error waiting for User Import Job %s to complete: %s (%s)", jobId, err, d.Get("completion_message").(string))
+    }
+
+    return resourceCognitoIDPUserImportJobRead(d, meta)
+}
+
+func resourceCognitoIDPUserImportJobRead(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    id := d.Id()
+
+    resp, err := cidpconn.DescribeUserImportJob(&cognitoidentityprovider.DescribeUserImportJobInput{
+        UserPoolId: aws.String(d.Get("user_pool_id").(string)),
+        JobId: aws.String(id),
+    })
+    if err != nil {
+        if isAWSErr(err, cognitoidentityprovider.ErrCodeResourceNotFoundException, "") {
+            log.Printf("[WARN] User Import Job %s not found, removing from state", id)
+            d.SetId("")
+            return nil
+        }
+        return fmt.Errorf("Error reading User Import Job %s: %s", id, err)
+    }
+
+    job := resp.UserImportJob
+    d.Set("job_name", job.JobName)
+    d.Set("cloudwatch_logs_role_arn", job.CloudWatchLogsRoleArn)
+    d.Set("status", job.Status)
+    d.Set("completion_message", job.CompletionMessage)
+    d.Set("imported_users", job.ImportedUsers)
+    d.Set("skipped_users", job.SkippedUsers)
+    d.Set("failed_users", job.FailedUsers)
+
+    return nil
+}
+
+func resourceCognitoIDPUserImportJobDelete(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    id := d.Id()
+
+    // Import jobs cannot be deleted, only stopped while they still run
+    switch d.Get("status").(string) {
+    case cognitoidentityprovider.UserImportJobStatusTypePending, cognitoidentityprovider.UserImportJobStatusTypeInProgress:
+        _, err := cidpconn.StopUserImportJob(&cognitoidentityprovider.StopUserImportJobInput{
+            UserPoolId: aws.String(d.Get("user_pool_id").(string)),
+            JobId: aws.String(id),
+        })
+        if err != nil && !isAWSErr(err, cognitoidentityprovider.ErrCodeResourceNotFoundException, "") {
+            return fmt.Errorf("Error stopping User Import Job %s: %s", id, err)
+        }
+    }
+
+    return nil
+}
+
+func resourceCognitoIDPUserImportJobCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+    if !d.NewValueKnown("csv_file") {
+        return d.SetNewComputed("csv_hash")
+    }
+
+    csvFile := d.Get("csv_file").(string)
+    content, err := ioutil.ReadFile(csvFile)
+    if err != nil {
+        // The CSV is only needed to start the job, so a finished job may
+        // outlive it
+        if d.Id() != "" && !d.HasChange("csv_file") && os.IsNotExist(err) {
+            log.Printf("[DEBUG] csv_file %s of User Import Job %s no longer exists, not comparing csv_hash", csvFile, d.Id())
+            return nil
+        }
+        return fmt.Errorf("Error reading csv_file: %s", err)
+    }
+
+    hash := sha256Hex(content)
+    if d.Id() != "" && hash != d.Get("csv_hash").(string) {
+        if err := d.SetNew("csv_hash", hash); err != nil {
+            return err
+        }
+        return d.ForceNew("csv_hash")
+    }
+
+    return nil
+}
+
+func cognitoIDPUserImportJobStateRefreshFunc(cidpconn *cognitoidentityprovider.CognitoIdentityProvider, userPoolId, jobId string) resource.StateRefreshFunc {
+    return func() (interface{}, string, error) {
+        resp, err := cidpconn.DescribeUserImportJob(&cognitoidentityprovider.DescribeUserImportJobInput{
+            UserPoolId: aws.String(userPoolId),
+            JobId: aws.String(jobId),
+        })
+        if err != nil {
+            return nil, "", err
+        }
+
+        return resp.UserImportJob, aws.StringValue(resp.UserImportJob.Status), nil
+    }
+}
+
+// validateCognitoIDPUserImportCSV checks that the CSV carries exactly the
+// columns GetCSVHeader returned, in any order, and that every row has as many
+// fields as the header. Most columns are optional attributes and may be empty.
+func validateCognitoIDPUserImportCSV(content []byte, header []string) error {
+    r := csv.NewReader(bytes.NewReader(content))
+
+    columns, err := r.Read()
+    if err != nil {
+        return fmt.Errorf("could not read the header row: %s", err)
+    }
+
+    expected := make(map[string]bool, len(header))
+    for _, h := range header {
+        expected[h] = true
+    }
+
+    seen := make(map[string]bool, len(columns))
+    for _, c := range columns {
+        c = strings.TrimSpace(c)
+        if !expected[c] {
+            return fmt.Errorf("unknown column %q", c)
+        }
+        if seen[c] {
+            return fmt.Errorf("duplicate column %q", c)
+        }
+        seen[c] = true
+    }
+
+    for _, h := range header {
+        if !seen[h] {
+            return fmt.Errorf("missing column %q", h)
+        }
+    }
+
+    // csv.Reader already rejects rows whose field count differs from the
+    // header row
+    line := 1
+    for {
+        _, err := r.Read()
+        if err == io.EOF {
+            break
+        }
+        line++
+        if err != nil {
+            return fmt.Errorf("line %d: %s", line, err)
+        }
+    }
+
+    return nil
+}
+
+// uploadCognitoIDPUserImportCSV PUTs the CSV to the presigned URL that
+// CreateUserImportJob hands out
+func uploadCognitoIDPUserImportCSV(url string, content []byte) error {
+    req, err := http.NewRequest("PUT", url, bytes.NewReader(content))
+    if err != nil {
+        return err
+    }
+    req.Header.Set("x-amz-server-side-encryption", "aws:kms")
+    req.Header.Set("Content-Type", "text/csv")
+
+    resp, err := cleanhttp.DefaultClient().Do(req)
+    if err != nil {
+        return err
+    }
+    defer resp.Body.Close()
+
+    if resp.StatusCode < 200 || resp.StatusCode > 299 {
+        body, _ := ioutil.ReadAll(resp.Body)
+        return fmt.Errorf("unexpected response %s: %s", resp.Status, body)
+    }
+
+    return nil
+}
@@ -0,0 +1,117 @@
+package aws
+
+import (
+    "io/ioutil"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+)
+
+func TestUploadCognitoIDPUserImportCSV(t *testing.T) {
+    content := []byte("cognito:username,email\njdoe,jdoe@example.com\n")
+
+    var method, sse, contentType, body string
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        method = r.Method
+        sse = r.Header.Get("x-amz-server-side-encryption")
+        contentType = r.Header.Get("Content-Type")
+        b, _ := ioutil.ReadAll(r.Body)
+        body = string(b)
+    }))
+    defer server.Close()
+
+    if err := uploadCognitoIDPUserImportCSV(server.URL+"/upload?X-Amz-Signature=abc", content); err != nil {
+        t.Fatalf("unexpected error: %s", err)
+    }
+
+    if method != "PUT" {
+        t.Errorf("expected PUT, got %s", method)
+    }
+    if sse != "aws:kms" {
+        t.Errorf("expected x-amz-server-side-encryption aws:kms, got %q", sse)
+    }
+    if contentType != "text/csv" {
+        t.Errorf("expected Content-Type text/csv, got %q", contentType)
+    }
+    if body != string(content) {
+        t.Errorf("expected body %q, got %q", content, body)
+    }
+}
+
+func TestUploadCognitoIDPUserImportCSV_errorResponse(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.WriteHeader(http.StatusForbidden)
+        w.Write([]byte("SignatureDoesNotMatch"))
+    }))
+    defer server.Close()
+
+    err := uploadCognitoIDPUserImportCSV(server.URL, []byte("cognito:username\n"))
+    if err == nil {
+        t.Fatal("expected an error for a 403 response")
+    }
+    if !strings.Contains(err.Error(), "SignatureDoesNotMatch") {
+        t.Errorf("expected the response body in the error, got: %s", err)
+    }
+}
+
+func TestValidateCognitoIDPUserImportCSV(t *testing.T) {
+    header := []string{"name", "email", "email_verified", "cognito:username"}
+
+    cases := []struct {
+        Name string
+        CSV string
+        Error string
+    }{
+        {
+            Name: "valid, columns in another order, empty values",
+            CSV: "cognito:username,email,email_verified,name\njdoe,jdoe@example.com,true,\n",
+        },
+        {
+            Name: "header only",
+            CSV: "name,email,email_verified,cognito:username\n",
+        },
+        {
+            Name: "missing column",
+            CSV: "name,email,cognito:username\nJohn,jdoe@example.com,jdoe\n",
+            Error: `missing column "email_verified"`,
+        },
+        {
+            Name: "duplicate column",
+            CSV: "name,email,email,email_verified,cognito:username\n",
+            Error: `duplicate column "email"`,
+        },
+        {
+            Name: "unknown column",
+            CSV: "name,email,email_verified,cognito:username,nickname\n",
+            Error: `unknown column "nickname"`,
+        },
+        {
+            Name: "ragged row",
+            CSV: "name,email,email_verified,cognito:username\nJohn,jdoe@example.com,true,jdoe\nJane,jane@example.com\n",
+            Error: "line 3",
+        },
+        {
+            Name: "empty file",
+            CSV: "",
+            Error: "could not read the header row",
+        },
+    }
+
+    for _, tc := range cases {
+        err := validateCognitoIDPUserImportCSV([]byte(tc.CSV), header)
+        if tc.Error == "" {
+            if err != nil {
+                t.Errorf("%s: unexpected error: %s", tc.Name, err)
+            }
+            continue
+        }
+        if err == nil {
+            t.Errorf("%s: expected an error containing %q", tc.Name, tc.Error)
+            continue
+        }
+        if !strings.Contains(err.Error(), tc.Error) {
+            t.Errorf("%s: expected an error containing %q, got: %s", tc.Name, tc.Error, err)
+        }
+    }
+}
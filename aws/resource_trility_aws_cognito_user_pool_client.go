@@ -0,0 +1,365 @@
+package aws
+
+import (
+    "fmt"
+    "log"
+
+    "github.com/hashicorp/terraform/helper/schema"
+    "github.com/hashicorp/terraform/helper/validation"
+
+    "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
+)
+
+func resourceTrilityAwsCognitoUserPoolClient() *schema.Resource {
+    return &schema.Resource{
+        Create: resourceCognitoIDPUserPoolClientCreate,
+        Read: resourceCognitoIDPUserPoolClientRead,
+        Update: resourceCognitoIDPUserPoolClientUpdate,
+        Delete: resourceCognitoIDPUserPoolClientDelete,
+        Importer: &schema.ResourceImporter{
+            State: resourceTrilityAwsCognitoUserPoolClientImport,
+        },
+
+        Schema: map[string]*schema.Schema{
+            "user_pool_id": &schema.Schema{
+                Type: schema.TypeString,
+                Required: true,
+                ForceNew: true,
+            },
+            "name": &schema.Schema{
+                Type: schema.TypeString,
+                Required: true,
+            },
+            "generate_secret": &schema.Schema{
+                Type: schema.TypeBool,
+                Optional: true,
+                Default: false,
+                ForceNew: true,
+            },
+            "client_secret": &schema.Schema{
+                Type: schema.TypeString,
+                Computed: true,
+                Sensitive: true,
+            },
+            "allowed_oauth_flows": &schema.Schema{
+                Type: schema.TypeSet,
+                Optional: true,
+                MaxItems: 3,
+                Elem: &schema.Schema{
+                    Type: schema.TypeString,
+                    ValidateFunc: validation.StringInSlice([]string{
+                        cognitoidentityprovider.OAuthFlowTypeCode,
+                        cognitoidentityprovider.OAuthFlowTypeImplicit,
+                        cognitoidentityprovider.OAuthFlowTypeClientCredentials,
+                    }, false),
+                },
+            },
+            "allowed_oauth_flows_user_pool_client": &schema.Schema{
+                Type: schema.TypeBool,
+                Optional: true,
+                Default: false,
+            },
+            "allowed_oauth_scopes": &schema.Schema{
+                Type: schema.TypeSet,
+                Optional: true,
+                Elem: &schema.Schema{Type: schema.TypeString},
+            },
+            "callback_urls": &schema.Schema{
+                Type: schema.TypeSet,
+                Optional: true,
+                Elem: &schema.Schema{Type: schema.TypeString},
+            },
+            "logout_urls": &schema.Schema{
+                Type: schema.TypeSet,
+                Optional: true,
+                Elem: &schema.Schema{Type: schema.TypeString},
+            },
+            "default_redirect_uri": &schema.Schema{
+                Type: schema.TypeString,
+                Optional: true,
+            },
+            "supported_identity_providers": &schema.Schema{
+                Type: schema.TypeSet,
+                Optional: true,
+                Elem: &schema.Schema{Type: schema.TypeString},
+            },
+            "explicit_auth_flows": &schema.Schema{
+                Type: schema.TypeSet,
+                Optional: true,
+                Computed: true,
+                Elem: &schema.Schema{
+                    Type: schema.TypeString,
+                    ValidateFunc: validation.StringInSlice([]string{
+                        cognitoidentityprovider.ExplicitAuthFlowsTypeAllowAdminUserPasswordAuth,
+                        cognitoidentityprovider.ExplicitAuthFlowsTypeAllowCustomAuth,
+                        cognitoidentityprovider.ExplicitAuthFlowsTypeAllowUserPasswordAuth,
+                        cognitoidentityprovider.ExplicitAuthFlowsTypeAllowUserSrpAuth,
+                        cognitoidentityprovider.ExplicitAuthFlowsTypeAllowRefreshTokenAuth,
+                        cognitoidentityprovider.ExplicitAuthFlowsTypeAllowUserAuth,
+                    }, false),
+                },
+            },
+            "access_token_validity": &schema.Schema{
+                Type: schema.TypeInt,
+                Optional: true,
+                Computed: true,
+            },
+            "id_token_validity": &schema.Schema{
+                Type: schema.TypeInt,
+                Optional: true,
+                Computed: true,
+            },
+            "refresh_token_validity": &schema.Schema{
+                Type: schema.TypeInt,
+                Optional: true,
+                Computed: true,
+            },
+            "token_validity_units": &schema.Schema{
+                Type: schema.TypeList,
+                Optional: true,
+                Computed: true,
+                MaxItems: 1,
+                Elem: &schema.Resource{
+                    Schema: map[string]*schema.Schema{
+                        "access_token": tokenValidityUnitSchema(),
+                        "id_token": tokenValidityUnitSchema(),
+                        "refresh_token": tokenValidityUnitSchema(),
+                    },
+                },
+            },
+            "read_attributes": &schema.Schema{
+                Type: schema.TypeSet,
+                Optional: true,
+                Elem: &schema.Schema{Type: schema.TypeString},
+            },
+            "write_attributes": &schema.Schema{
+                Type: schema.TypeSet,
+                Optional: true,
+                Elem: &schema.Schema{Type: schema.TypeString},
+            },
+            "prevent_user_existence_errors": &schema.Schema{
+                Type: schema.TypeString,
+                Optional: true,
+                Computed: true,
+                ValidateFunc: validation.StringInSlice([]string{
+                    cognitoidentityprovider.PreventUserExistenceErrorTypesLegacy,
+                    cognitoidentityprovider.PreventUserExistenceErrorTypesEnabled,
+                }, false),
+            },
+            "enable_token_revocation": &schema.Schema{
+                Type: schema.TypeBool,
+                Optional: true,
+                Default: true,
+            },
+        },
+    }
+}
+
+func tokenValidityUnitSchema() *schema.Schema {
+    return &schema.Schema{
+        Type: schema.TypeString,
+        Optional: true,
+        Default: cognitoidentityprovider.TimeUnitsTypeHours,
+        ValidateFunc: validation.StringInSlice([]string{
+            cognitoidentityprovider.TimeUnitsTypeSeconds,
+            cognitoidentityprovider.TimeUnitsTypeMinutes,
+            cognitoidentityprovider.TimeUnitsTypeHours,
+            cognitoidentityprovider.TimeUnitsTypeDays,
+        }, false),
+    }
+}
+
+func resourceCognitoIDPUserPoolClientCreate(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    name := d.Get("name").(string)
+
+    params := &cognitoidentityprovider.CreateUserPoolClientInput{
+        UserPoolId: aws.String(d.Get("user_pool_id").(string)),
+        ClientName: aws.String(name),
+        GenerateSecret: aws.Bool(d.Get("generate_secret").(bool)),
+        AllowedOAuthFlowsUserPoolClient: aws.Bool(d.Get("allowed_oauth_flows_user_pool_client").(bool)),
+        AllowedOAuthFlows: expandStringSet(d.Get("allowed_oauth_flows").(*schema.Set)),
+        AllowedOAuthScopes: expandStringSet(d.Get("allowed_oauth_scopes").(*schema.Set)),
+        CallbackURLs: expandStringSet(d.Get("callback_urls").(*schema.Set)),
+        LogoutURLs: expandStringSet(d.Get("logout_urls").(*schema.Set)),
+        SupportedIdentityProviders: expandStringSet(d.Get("supported_identity_providers").(*schema.Set)),
+        ReadAttributes: expandStringSet(d.Get("read_attributes").(*schema.Set)),
+        WriteAttributes: expandStringSet(d.Get("write_attributes").(*schema.Set)),
+        EnableTokenRevocation: aws.Bool(d.Get("enable_token_revocation").(bool)),
+    }
+
+    if v, ok := d.GetOk("default_redirect_uri"); ok {
+        params.DefaultRedirectURI = aws.String(v.(string))
+    }
+
+    if v, ok := d.GetOk("explicit_auth_flows"); ok {
+        params.ExplicitAuthFlows = expandStringSet(v.(*schema.Set))
+    }
+
+    if v, ok := d.GetOk("access_token_validity"); ok {
+        params.AccessTokenValidity = aws.Int64(int64(v.(int)))
+    }
+
+    if v, ok := d.GetOk("id_token_validity"); ok {
+        params.IdTokenValidity = aws.Int64(int64(v.(int)))
+    }
+
+    if v, ok := d.GetOk("refresh_token_validity"); ok {
+        params.RefreshTokenValidity = aws.Int64(int64(v.(int)))
+    }
+
+    if v, ok := d.GetOk("token_validity_units"); ok && v.([]interface{})[0] != nil {
+        params.TokenValidityUnits = expandTokenValidityUnits(v.([]interface{})[0].(map[string]interface{}))
+    }
+
+    if v, ok := d.GetOk("prevent_user_existence_errors"); ok {
+        params.PreventUserExistenceErrors = aws.String(v.(string))
+    }
+
+    resp, err := cidpconn.CreateUserPoolClient(params)
+    if err != nil {
+        return fmt.Errorf("Error creating User Pool Client %s: %s", name, err)
+    }
+
+    d.SetId(*resp.UserPoolClient.ClientId)
+    return resourceCognitoIDPUserPoolClientRead(d, meta)
+}
+
+func resourceCognitoIDPUserPoolClientRead(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    id := d.Id()
+
+    params := &cognitoidentityprovider.DescribeUserPoolClientInput{
+        UserPoolId: aws.String(d.Get("user_pool_id").(string)),
+        ClientId: aws.String(id),
+    }
+
+    resp, err := cidpconn.DescribeUserPoolClient(params)
+    if err != nil {
+        if isAWSErr(err, cognitoidentityprovider.ErrCodeResourceNotFoundException, "") {
+            log.Printf("[WARN] User Pool Client %s not found, removing from state", id)
+            d.SetId("")
+            return nil
+        }
+        return fmt.Errorf("Error reading User Pool Client %s: %s", id, err)
+    }
+
+    client := resp.UserPoolClient
+    d.Set("user_pool_id", client.UserPoolId)
+    d.Set("name", client.ClientName)
+    d.Set("client_secret", client.ClientSecret)
+    d.Set("generate_secret", client.ClientSecret != nil)
+    d.Set("allowed_oauth_flows_user_pool_client", client.AllowedOAuthFlowsUserPoolClient)
+    d.Set("default_redirect_uri", client.DefaultRedirectURI)
+    d.Set("access_token_validity", client.AccessTokenValidity)
+    d.Set("id_token_validity", client.IdTokenValidity)
+    d.Set("refresh_token_validity", client.RefreshTokenValidity)
+    d.Set("prevent_user_existence_errors", client.PreventUserExistenceErrors)
+    d.Set("enable_token_revocation", client.EnableTokenRevocation)
+
+    sets := map[string][]*string{
+        "allowed_oauth_flows": client.AllowedOAuthFlows,
+        "allowed_oauth_scopes": client.AllowedOAuthScopes,
+        "callback_urls": client.CallbackURLs,
+        "logout_urls": client.LogoutURLs,
+        "supported_identity_providers": client.SupportedIdentityProviders,
+        "explicit_auth_flows": client.ExplicitAuthFlows,
+        "read_attributes": client.ReadAttributes,
+        "write_attributes": client.WriteAttributes,
+    }
+    for k, v := range sets {
+        if err := d.Set(k, flattenStringSet(v)); err != nil {
+            return fmt.Errorf("Error setting %s for User Pool Client %s: %s", k, id, err)
+        }
+    }
+
+    if err := d.Set("token_validity_units", flattenTokenValidityUnits(client.TokenValidityUnits)); err != nil {
+        return fmt.Errorf("Error setting token_validity_units for User Pool Client %s: %s", id, err)
+    }
+
+    return nil
+}
+
+func resourceCognitoIDPUserPoolClientUpdate(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    id := d.Id()
+
+    // Like UpdateUserPool, anything left out of the request is reset
+    params := &cognitoidentityprovider.UpdateUserPoolClientInput{
+        UserPoolId: aws.String(d.Get("user_pool_id").(string)),
+        ClientId: aws.String(id),
+        ClientName: aws.String(d.Get("name").(string)),
+        AllowedOAuthFlowsUserPoolClient: aws.Bool(d.Get("allowed_oauth_flows_user_pool_client").(bool)),
+        AllowedOAuthFlows: expandStringSet(d.Get("allowed_oauth_flows").(*schema.Set)),
+        AllowedOAuthScopes: expandStringSet(d.Get("allowed_oauth_scopes").(*schema.Set)),
+        CallbackURLs: expandStringSet(d.Get("callback_urls").(*schema.Set)),
+        LogoutURLs: expandStringSet(d.Get("logout_urls").(*schema.Set)),
+        SupportedIdentityProviders: expandStringSet(d.Get("supported_identity_providers").(*schema.Set)),
+        ExplicitAuthFlows: expandStringSet(d.Get("explicit_auth_flows").(*schema.Set)),
+        ReadAttributes: expandStringSet(d.Get("read_attributes").(*schema.Set)),
+        WriteAttributes: expandStringSet(d.Get("write_attributes").(*schema.Set)),
+        AccessTokenValidity: aws.Int64(int64(d.Get("access_token_validity").(int))),
+        IdTokenValidity: aws.Int64(int64(d.Get("id_token_validity").(int))),
+        RefreshTokenValidity: aws.Int64(int64(d.Get("refresh_token_validity").(int))),
+        EnableTokenRevocation: aws.Bool(d.Get("enable_token_revocation").(bool)),
+    }
+
+    if v, ok := d.GetOk("default_redirect_uri"); ok {
+        params.DefaultRedirectURI = aws.String(v.(string))
+    }
+
+    if v, ok := d.GetOk("token_validity_units"); ok && v.([]interface{})[0] != nil {
+        params.TokenValidityUnits = expandTokenValidityUnits(v.([]interface{})[0].(map[string]interface{}))
+    }
+
+    if v, ok := d.GetOk("prevent_user_existence_errors"); ok {
+        params.PreventUserExistenceErrors = aws.String(v.(string))
+    }
+
+    _, err := cidpconn.UpdateUserPoolClient(params)
+    if err != nil {
+        return fmt.Errorf("Error updating User Pool Client %s: %s", id, err)
+    }
+
+    return resourceCognitoIDPUserPoolClientRead(d, meta)
+}
+
+func resourceCognitoIDPUserPoolClientDelete(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    id := d.Id()
+
+    params := &cognitoidentityprovider.DeleteUserPoolClientInput{
+        UserPoolId: aws.String(d.Get("user_pool_id").(string)),
+        ClientId: aws.String(id),
+    }
+
+    _, err := cidpconn.DeleteUserPoolClient(params)
+    if err != nil && !isAWSErr(err, cognitoidentityprovider.ErrCodeResourceNotFoundException, "") {
+        return fmt.Errorf("Error removing User Pool Client %s: %s", id, err)
+    }
+
+    return nil
+}
+
+func expandTokenValidityUnits(m map[string]interface{}) *cognitoidentityprovider.TokenValidityUnitsType {
+    return &cognitoidentityprovider.TokenValidityUnitsType{
+        AccessToken: aws.String(m["access_token"].(string)),
+        IdToken: aws.String(m["id_token"].(string)),
+        RefreshToken: aws.String(m["refresh_token"].(string)),
+    }
+}
+
+func flattenTokenValidityUnits(tvu *cognitoidentityprovider.TokenValidityUnitsType) []map[string]interface{} {
+    if tvu == nil {
+        return nil
+    }
+
+    m := map[string]interface{}{
+        "access_token": aws.StringValue(tvu.AccessToken),
+        "id_token": aws.StringValue(tvu.IdToken),
+        "refresh_token": aws.StringValue(tvu.RefreshToken),
+    }
+
+    return []map[string]interface{}{m}
+}
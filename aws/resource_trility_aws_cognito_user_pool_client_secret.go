@@ -0,0 +1,140 @@
+package aws
+
+import (
+    "fmt"
+    "log"
+    "time"
+
+    "github.com/hashicorp/terraform/helper/schema"
+
+    "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
+)
+
+// Every attribute is ForceNew: a secret is never changed in place, rotation
+// means adding a new one (create_before_destroy) and deleting the old one
+func resourceTrilityAwsCognitoUserPoolClientSecret() *schema.Resource {
+    return &schema.Resource{
+        Create: resourceCognitoIDPUserPoolClientSecretCreate,
+        Read: resourceCognitoIDPUserPoolClientSecretRead,
+        Delete: resourceCognitoIDPUserPoolClientSecretDelete,
+
+        Schema: map[string]*schema.Schema{
+            "user_pool_id": &schema.Schema{
+                Type: schema.TypeString,
+                Required: true,
+                ForceNew: true,
+            },
+            "client_id": &schema.Schema{
+                Type: schema.TypeString,
+                Required: true,
+                ForceNew: true,
+            },
+            // Cognito generates the value unless one is supplied
+            "client_secret": &schema.Schema{
+                Type: schema.TypeString,
+                Optional: true,
+                Computed: true,
+                ForceNew: true,
+                Sensitive: true,
+            },
+            "client_secret_id": &schema.Schema{
+                Type: schema.TypeString,
+                Computed: true,
+            },
+            "creation_date": &schema.Schema{
+                Type: schema.TypeString,
+                Computed: true,
+            },
+        },
+    }
+}
+
+func resourceCognitoIDPUserPoolClientSecretCreate(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    clientId := d.Get("client_id").(string)
+
+    params := &cognitoidentityprovider.AddUserPoolClientSecretInput{
+        UserPoolId: aws.String(d.Get("user_pool_id").(string)),
+        ClientId: aws.String(clientId),
+    }
+
+    if v, ok := d.GetOk("client_secret"); ok {
+        params.ClientSecret = aws.String(v.(string))
+    }
+
+    resp, err := cidpconn.AddUserPoolClientSecret(params)
+    if err != nil {
+        return fmt.Errorf("Error adding secret to User Pool Client %s: %s", clientId, err)
+    }
+
+    // The secret value is only ever returned here
+    secret := resp.ClientSecretDescriptor
+    d.SetId(*secret.ClientSecretId)
+    d.Set("client_secret", secret.ClientSecretValue)
+
+    return resourceCognitoIDPUserPoolClientSecretRead(d, meta)
+}
+
+func resourceCognitoIDPUserPoolClientSecretRead(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    id := d.Id()
+
+    params := &cognitoidentityprovider.ListUserPoolClientSecretsInput{
+        UserPoolId: aws.String(d.Get("user_pool_id").(string)),
+        ClientId: aws.String(d.Get("client_id").(string)),
+    }
+
+    var secret *cognitoidentityprovider.ClientSecretDescriptorType
+    for {
+        resp, err := cidpconn.ListUserPoolClientSecrets(params)
+        if err != nil {
+            if isAWSErr(err, cognitoidentityprovider.ErrCodeResourceNotFoundException, "") {
+                break
+            }
+            return fmt.Errorf("Error reading User Pool Client secret %s: %s", id, err)
+        }
+
+        for _, s := range resp.ClientSecrets {
+            if aws.StringValue(s.ClientSecretId) == id {
+                secret = s
+            }
+        }
+
+        if secret != nil || resp.NextToken == nil {
+            break
+        }
+        params.NextToken = resp.NextToken
+    }
+
+    if secret == nil {
+        log.Printf("[WARN] User Pool Client secret %s not found, removing from state", id)
+        d.SetId("")
+        return nil
+    }
+
+    d.Set("client_secret_id", secret.ClientSecretId)
+    if secret.ClientSecretCreateDate != nil {
+        d.Set("creation_date", secret.ClientSecretCreateDate.Format(time.RFC3339))
+    }
+
+    return nil
+}
+
+func resourceCognitoIDPUserPoolClientSecretDelete(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    id := d.Id()
+
+    params := &cognitoidentityprovider.DeleteUserPoolClientSecretInput{
+        UserPoolId: aws.String(d.Get("user_pool_id").(string)),
+        ClientId: aws.String(d.Get("client_id").(string)),
+        ClientSecretId: aws.String(id),
+    }
+
+    _, err := cidpconn.DeleteUserPoolClientSecret(params)
+    if err != nil && !isAWSErr(err, cognitoidentityprovider.ErrCodeResourceNotFoundException, "") {
+        return fmt.Errorf("Error removing User Pool Client secret %s: %s", id, err)
+    }
+
+    return nil
+}
@@ -0,0 +1,264 @@
+package aws
+
+import (
+    "fmt"
+    "log"
+    "time"
+
+    "github.com/hashicorp/terraform/helper/resource"
+    "github.com/hashicorp/terraform/helper/schema"
+    "github.com/hashicorp/terraform/helper/validation"
+
+    "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
+)
+
+// Custom domains are fronted by CloudFront, and every CloudFront
+// distribution lives in this hosted zone
+const cloudFrontHostedZoneId = "Z2FDTNDATAQYW2"
+
+func resourceTrilityAwsCognitoUserPoolDomain() *schema.Resource {
+    return &schema.Resource{
+        Create: resourceCognitoIDPUserPoolDomainCreate,
+        Read: resourceCognitoIDPUserPoolDomainRead,
+        Update: resourceCognitoIDPUserPoolDomainUpdate,
+        Delete: resourceCognitoIDPUserPoolDomainDelete,
+        Importer: &schema.ResourceImporter{
+            State: schema.ImportStatePassthrough,
+        },
+
+        // Prefix domains are ready within a minute, custom domains wait on
+        // their CloudFront distribution
+        Timeouts: &schema.ResourceTimeout{
+            Create: schema.DefaultTimeout(60 * time.Minute),
+            Update: schema.DefaultTimeout(60 * time.Minute),
+            Delete: schema.DefaultTimeout(60 * time.Minute),
+        },
+
+        Schema: map[string]*schema.Schema{
+            // Either a prefix for <domain>.auth.<region>.amazoncognito.com
+            // or a fully qualified custom domain with certificate_arn
+            "domain": &schema.Schema{
+                Type: schema.TypeString,
+                Required: true,
+                ForceNew: true,
+            },
+            "user_pool_id": &schema.Schema{
+                Type: schema.TypeString,
+                Required: true,
+                ForceNew: true,
+            },
+            "certificate_arn": &schema.Schema{
+                Type: schema.TypeString,
+                Optional: true,
+            },
+            "managed_login_version": &schema.Schema{
+                Type: schema.TypeInt,
+                Optional: true,
+                Computed: true,
+                ValidateFunc: validation.IntBetween(1, 2),
+            },
+            "aws_account_id": &schema.Schema{
+                Type: schema.TypeString,
+                Computed: true,
+            },
+            "cloudfront_distribution": &schema.Schema{
+                Type: schema.TypeString,
+                Computed: true,
+            },
+            "cloudfront_distribution_zone_id": &schema.Schema{
+                Type: schema.TypeString,
+                Computed: true,
+            },
+            "s3_bucket": &schema.Schema{
+                Type: schema.TypeString,
+                Computed: true,
+            },
+            "version": &schema.Schema{
+                Type: schema.TypeString,
+                Computed: true,
+            },
+        },
+    }
+}
+
+func resourceCognitoIDPUserPoolDomainCreate(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    domain := d.Get("domain").(string)
+
+    params := &cognitoidentityprovider.CreateUserPoolDomainInput{
+        Domain: aws.String(domain),
+        UserPoolId: aws.String(d.Get("user_pool_id").(string)),
+    }
+
+    // Custom domains need a certificate
+    if v, ok := d.GetOk("certificate_arn"); ok {
+        params.CustomDomainConfig = &cognitoidentityprovider.CustomDomainConfigType{
+            CertificateArn: aws.String(v.(string)),
+        }
+    }
+
+    if v, ok := d.GetOk("managed_login_version"); ok {
+        params.ManagedLoginVersion = aws.Int64(int64(v.(int)))
+    }
+
+    _, err := cidpconn.CreateUserPoolDomain(params)
+    if err != nil {
+        return fmt.Errorf("Error creating User Pool Domain %s: %s", domain, err)
+    }
+
+    d.SetId(domain)
+
+    if err := waitForCognitoIDPUserPoolDomain(cidpconn, domain, d.Timeout(schema.TimeoutCreate)); err != nil {
+        return err
+    }
+
+    return resourceCognitoIDPUserPoolDomainRead(d, meta)
+}
+
+func resourceCognitoIDPUserPoolDomainRead(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    id := d.Id()
+
+    params := &cognitoidentityprovider.DescribeUserPoolDomainInput{
+        Domain: aws.String(id),
+    }
+
+    resp, err := cidpconn.DescribeUserPoolDomain(params)
+    if err != nil {
+        return fmt.Errorf("Error reading User Pool Domain %s: %s", id, err)
+    }
+
+    // An unknown domain comes back as an empty description, not an error
+    desc := resp.DomainDescription
+    if desc == nil || desc.Domain == nil {
+        log.Printf("[WARN] User Pool Domain %s not found, removing from state", id)
+        d.SetId("")
+        return nil
+    }
+
+    d.Set("domain", desc.Domain)
+    d.Set("user_pool_id", desc.UserPoolId)
+    d.Set("managed_login_version", desc.ManagedLoginVersion)
+    d.Set("aws_account_id", desc.AWSAccountId)
+    d.Set("cloudfront_distribution", desc.CloudFrontDistribution)
+    d.Set("cloudfront_distribution_zone_id", cloudFrontHostedZoneId)
+    d.Set("s3_bucket", desc.S3Bucket)
+    d.Set("version", desc.Version)
+
+    if desc.CustomDomainConfig != nil {
+        d.Set("certificate_arn", desc.CustomDomainConfig.CertificateArn)
+    } else {
+        d.Set("certificate_arn", "")
+    }
+
+    return nil
+}
+
+func resourceCognitoIDPUserPoolDomainUpdate(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    id := d.Id()
+
+    params := &cognitoidentityprovider.UpdateUserPoolDomainInput{
+        Domain: aws.String(id),
+        UserPoolId: aws.String(d.Get("user_pool_id").(string)),
+    }
+
+    if v, ok := d.GetOk("certificate_arn"); ok {
+        params.CustomDomainConfig = &cognitoidentityprovider.CustomDomainConfigType{
+            CertificateArn: aws.String(v.(string)),
+        }
+    }
+
+    if v, ok := d.GetOk("managed_login_version"); ok {
+        params.ManagedLoginVersion = aws.Int64(int64(v.(int)))
+    }
+
+    _, err := cidpconn.UpdateUserPoolDomain(params)
+    if err != nil {
+        return fmt.Errorf("Error updating User Pool Domain %s: %s", id, err)
+    }
+
+    if err := waitForCognitoIDPUserPoolDomain(cidpconn, id, d.Timeout(schema.TimeoutUpdate)); err != nil {
+        return err
+    }
+
+    return resourceCognitoIDPUserPoolDomainRead(d, meta)
+}
+
+func resourceCognitoIDPUserPoolDomainDelete(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    id := d.Id()
+
+    params := &cognitoidentityprovider.DeleteUserPoolDomainInput{
+        Domain: aws.String(id),
+        UserPoolId: aws.String(d.Get("user_pool_id").(string)),
+    }
+
+    _, err := cidpconn.DeleteUserPoolDomain(params)
+    if err != nil {
+        if isAWSErr(err, cognitoidentityprovider.ErrCodeResourceNotFoundException, "") {
+            return nil
+        }
+        return fmt.Errorf("Error removing User Pool Domain %s: %s", id, err)
+    }
+
+    // The pool cannot take another domain until this one is fully gone
+    stateConf := &resource.StateChangeConf{
+        Pending: []string{
+            cognitoidentityprovider.DomainStatusTypeUpdating,
+            cognitoidentityprovider.DomainStatusTypeDeleting,
+        },
+        Target: []string{""},
+        Refresh: cognitoIDPUserPoolDomainStateRefreshFunc(cidpconn, id),
+        Timeout: d.Timeout(schema.TimeoutDelete),
+        MinTimeout: 5 * time.Second,
+    }
+
+    _, err = stateConf.WaitForState()
+    if err != nil {
+        return fmt.Errorf("Error waiting for User Pool Domain %s to be removed: %s", id, err)
+    }
+
+    return nil
+}
+
+func waitForCognitoIDPUserPoolDomain(cidpconn *cognitoidentityprovider.CognitoIdentityProvider, domain string, timeout time.Duration) error {
+    stateConf := &resource.StateChangeConf{
+        Pending: []string{
+            cognitoidentityprovider.DomainStatusTypeCreating,
+            cognitoidentityprovider.DomainStatusTypeUpdating,
+        },
+        Target: []string{
+            cognitoidentityprovider.DomainStatusTypeActive,
+        },
+        Refresh: cognitoIDPUserPoolDomainStateRefreshFunc(cidpconn, domain),
+        Timeout: timeout,
+        MinTimeout: 5 * time.Second,
+    }
+
+    _, err := stateConf.WaitForState()
+    if err != nil {
+        return fmt.Errorf("Error waiting for User Pool Domain %s to become active: %s", domain, err)
+    }
+
+    return nil
+}
+
+func cognitoIDPUserPoolDomainStateRefreshFunc(cidpconn *cognitoidentityprovider.CognitoIdentityProvider, domain string) resource.StateRefreshFunc {
+    return func() (interface{}, string, error) {
+        resp, err := cidpconn.DescribeUserPoolDomain(&cognitoidentityprovider.DescribeUserPoolDomainInput{
+            Domain: aws.String(domain),
+        })
+        if err != nil {
+            return nil, "", err
+        }
+
+        desc := resp.DomainDescription
+        if desc == nil || desc.Domain == nil {
+            return resp, "", nil
+        }
+
+        return desc, aws.StringValue(desc.Status), nil
+    }
+}
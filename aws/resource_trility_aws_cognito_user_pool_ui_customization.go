@@ -0,0 +1,195 @@
+package aws
+
+import (
+    "crypto/sha256"
+    "encoding/hex"
+    "fmt"
+    "io/ioutil"
+    "log"
+    "strings"
+    "time"
+
+    "github.com/hashicorp/terraform/helper/schema"
+
+    "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
+)
+
+// Cognito's pseudo client ID for customization that applies to every client
+const cognitoIDPAllClients = "ALL"
+
+func resourceTrilityAwsCognitoUserPoolUICustomization() *schema.Resource {
+    return &schema.Resource{
+        Create: resourceCognitoIDPUserPoolUICustomizationPut,
+        Read: resourceCognitoIDPUserPoolUICustomizationRead,
+        Update: resourceCognitoIDPUserPoolUICustomizationPut,
+        Delete: resourceCognitoIDPUserPoolUICustomizationDelete,
+
+        CustomizeDiff: resourceCognitoIDPUserPoolUICustomizationCustomizeDiff,
+
+        Schema: map[string]*schema.Schema{
+            "user_pool_id": &schema.Schema{
+                Type: schema.TypeString,
+                Required: true,
+                ForceNew: true,
+            },
+            "client_id": &schema.Schema{
+                Type: schema.TypeString,
+                Optional: true,
+                Default: cognitoIDPAllClients,
+                ForceNew: true,
+            },
+            "css": &schema.Schema{
+                Type: schema.TypeString,
+                Optional: true,
+            },
+            // Path to a local PNG or JPEG logo
+            "image_file": &schema.Schema{
+                Type: schema.TypeString,
+                Optional: true,
+            },
+            // Cognito only hands back a URL for the logo, so changes to the
+            // file are detected through its hash
+            "image_hash": &schema.Schema{
+                Type: schema.TypeString,
+                Computed: true,
+            },
+            "image_url": &schema.Schema{
+                Type: schema.TypeString,
+                Computed: true,
+            },
+            "css_version": &schema.Schema{
+                Type: schema.TypeString,
+                Computed: true,
+            },
+            "last_modified_date": &schema.Schema{
+                Type: schema.TypeString,
+                Computed: true,
+            },
+        },
+    }
+}
+
+func resourceCognitoIDPUserPoolUICustomizationPut(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    userPoolId := d.Get("user_pool_id").(string)
+    clientId := d.Get("client_id").(string)
+    id := fmt.Sprintf("%s/%s", userPoolId, clientId)
+
+    params := &cognitoidentityprovider.SetUICustomizationInput{
+        UserPoolId: aws.String(userPoolId),
+        ClientId: aws.String(clientId),
+    }
+
+    if v, ok := d.GetOk("css"); ok {
+        params.CSS = aws.String(v.(string))
+    }
+
+    imageHash := ""
+    if v, ok := d.GetOk("image_file"); ok {
+        image, err := ioutil.ReadFile(v.(string))
+        if err != nil {
+            return fmt.Errorf("Error reading image_file for UI customization %s: %s", id, err)
+        }
+        params.ImageFile = image
+        imageHash = sha256Hex(image)
+    }
+
+    _, err := cidpconn.SetUICustomization(params)
+    if err != nil {
+        return fmt.Errorf("Error setting UI customization %s: %s", id, err)
+    }
+
+    d.SetId(id)
+    d.Set("image_hash", imageHash)
+    return resourceCognitoIDPUserPoolUICustomizationRead(d, meta)
+}
+
+func resourceCognitoIDPUserPoolUICustomizationRead(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    id := d.Id()
+
+    parts := strings.SplitN(id, "/", 2)
+    if len(parts) != 2 {
+        return fmt.Errorf("Wrong format of UI customization ID (%s), use: 'user-pool-id/client-id'", id)
+    }
+
+    params := &cognitoidentityprovider.GetUICustomizationInput{
+        UserPoolId: aws.String(parts[0]),
+        ClientId: aws.String(parts[1]),
+    }
+
+    resp, err := cidpconn.GetUICustomization(params)
+    if err != nil {
+        if isAWSErr(err, cognitoidentityprovider.ErrCodeResourceNotFoundException, "") {
+            log.Printf("[WARN] UI customization %s not found, removing from state", id)
+            d.SetId("")
+            return nil
+        }
+        return fmt.Errorf("Error reading UI customization %s: %s", id, err)
+    }
+
+    // A client without its own customization falls back to the pool-wide
+    // one, which means ours is gone
+    ui := resp.UICustomization
+    if ui == nil || aws.StringValue(ui.ClientId) != parts[1] || (ui.CSS == nil && ui.ImageUrl == nil) {
+        log.Printf("[WARN] UI customization %s not found, removing from state", id)
+        d.SetId("")
+        return nil
+    }
+
+    d.Set("user_pool_id", parts[0])
+    d.Set("client_id", parts[1])
+    d.Set("css", ui.CSS)
+    d.Set("css_version", ui.CSSVersion)
+    d.Set("image_url", ui.ImageUrl)
+    if ui.LastModifiedDate != nil {
+        d.Set("last_modified_date", ui.LastModifiedDate.Format(time.RFC3339))
+    }
+
+    return nil
+}
+
+func resourceCognitoIDPUserPoolUICustomizationDelete(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    id := d.Id()
+
+    // Setting neither CSS nor an image resets the hosted UI to its defaults
+    params := &cognitoidentityprovider.SetUICustomizationInput{
+        UserPoolId: aws.String(d.Get("user_pool_id").(string)),
+        ClientId: aws.String(d.Get("client_id").(string)),
+    }
+
+    _, err := cidpconn.SetUICustomization(params)
+    if err != nil && !isAWSErr(err, cognitoidentityprovider.ErrCodeResourceNotFoundException, "") {
+        return fmt.Errorf("Error removing UI customization %s: %s", id, err)
+    }
+
+    return nil
+}
+
+func resourceCognitoIDPUserPoolUICustomizationCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+    if !d.NewValueKnown("image_file") {
+        return d.SetNewComputed("image_hash")
+    }
+
+    hash := ""
+    if v, ok := d.GetOk("image_file"); ok {
+        image, err := ioutil.ReadFile(v.(string))
+        if err != nil {
+            return fmt.Errorf("Error reading image_file: %s", err)
+        }
+        hash = sha256Hex(image)
+    }
+
+    if hash != d.Get("image_hash").(string) {
+        return d.SetNew("image_hash", hash)
+    }
+
+    return nil
+}
+
+func sha256Hex(b []byte) string {
+    sum := sha256.Sum256(b)
+    return hex.EncodeToString(sum[:])
+}
@@ -2,13 +2,21 @@ package aws
 
 import (
     "bytes"
+    "encoding/json"
     "fmt"
+    "log"
+    "regexp"
+    "strings"
+    "time"
 
     "github.com/hashicorp/terraform/helper/hashcode"
+    "github.com/hashicorp/terraform/helper/resource"
     "github.com/hashicorp/terraform/helper/schema"
+    "github.com/hashicorp/terraform/helper/validation"
 
     "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
+	"github.com/aws/aws-sdk-go/service/iam"
 )
 
 func resourceTrilityAwsCognitoIDPUserPool() *schema.Resource {
@@ -18,6 +26,8 @@ func resourceTrilityAwsCognitoIDPUserPool() *schema.Resource {
         Update: resourceCognitoIDPUserPoolUpdate,
         Delete: resourceCognitoIDPUserPoolDelete,
 
+        CustomizeDiff: resourceCognitoIDPUserPoolCustomizeDiff,
+
         Schema: map[string]*schema.Schema{
             "poolname": &schema.Schema{
                 Type: schema.TypeString,
@@ -62,12 +72,463 @@ func resourceTrilityAwsCognitoIDPUserPool() *schema.Resource {
                     },
                 },
             },
+            "lambda_config": &schema.Schema{
+                Type: schema.TypeList,
+                Optional: true,
+                MaxItems: 1,
+                Elem: &schema.Resource{
+                    Schema: map[string]*schema.Schema{
+                        "create_auth_challenge": {
+                            Type: schema.TypeString,
+                            Optional: true,
+                        },
+                        "custom_email_sender": {
+                            Type: schema.TypeList,
+                            Optional: true,
+                            MaxItems: 1,
+                            Elem: &schema.Resource{
+                                Schema: map[string]*schema.Schema{
+                                    "lambda_arn": {
+                                        Type: schema.TypeString,
+                                        Required: true,
+                                    },
+                                    "lambda_version": {
+                                        Type: schema.TypeString,
+                                        Required: true,
+                                        ValidateFunc: validation.StringInSlice([]string{
+                                            cognitoidentityprovider.CustomEmailSenderLambdaVersionTypeV10,
+                                        }, false),
+                                    },
+                                },
+                            },
+                        },
+                        "custom_message": {
+                            Type: schema.TypeString,
+                            Optional: true,
+                        },
+                        "custom_sms_sender": {
+                            Type: schema.TypeList,
+                            Optional: true,
+                            MaxItems: 1,
+                            Elem: &schema.Resource{
+                                Schema: map[string]*schema.Schema{
+                                    "lambda_arn": {
+                                        Type: schema.TypeString,
+                                        Required: true,
+                                    },
+                                    "lambda_version": {
+                                        Type: schema.TypeString,
+                                        Required: true,
+                                        ValidateFunc: validation.StringInSlice([]string{
+                                            cognitoidentityprovider.CustomSMSSenderLambdaVersionTypeV10,
+                                        }, false),
+                                    },
+                                },
+                            },
+                        },
+                        "define_auth_challenge": {
+                            Type: schema.TypeString,
+                            Optional: true,
+                        },
+                        "kms_key_id": {
+                            Type: schema.TypeString,
+                            Optional: true,
+                        },
+                        "post_authentication": {
+                            Type: schema.TypeString,
+                            Optional: true,
+                        },
+                        "post_confirmation": {
+                            Type: schema.TypeString,
+                            Optional: true,
+                        },
+                        "pre_authentication": {
+                            Type: schema.TypeString,
+                            Optional: true,
+                        },
+                        "pre_sign_up": {
+                            Type: schema.TypeString,
+                            Optional: true,
+                        },
+                        // Cognito mirrors the ARN of pre_token_generation_config
+                        // in here, which flattenLambdaConfig hides again
+                        "pre_token_generation": {
+                            Type: schema.TypeString,
+                            Optional: true,
+                        },
+                        "pre_token_generation_config": {
+                            Type: schema.TypeList,
+                            Optional: true,
+                            MaxItems: 1,
+                            Elem: &schema.Resource{
+                                Schema: map[string]*schema.Schema{
+                                    "lambda_arn": {
+                                        Type: schema.TypeString,
+                                        Required: true,
+                                    },
+                                    "lambda_version": {
+                                        Type: schema.TypeString,
+                                        Required: true,
+                                        ValidateFunc: validation.StringInSlice([]string{
+                                            cognitoidentityprovider.PreTokenGenerationLambdaVersionTypeV10,
+                                            cognitoidentityprovider.PreTokenGenerationLambdaVersionTypeV20,
+                                        }, false),
+                                    },
+                                },
+                            },
+                        },
+                        "user_migration": {
+                            Type: schema.TypeString,
+                            Optional: true,
+                        },
+                        "verify_auth_challenge_response": {
+                            Type: schema.TypeString,
+                            Optional: true,
+                        },
+                    },
+                },
+            },
+            "email_configuration": &schema.Schema{
+                Type: schema.TypeList,
+                Optional: true,
+                Computed: true,
+                MaxItems: 1,
+                Elem: &schema.Resource{
+                    Schema: map[string]*schema.Schema{
+                        "configuration_set": {
+                            Type: schema.TypeString,
+                            Optional: true,
+                        },
+                        "email_sending_account": {
+                            Type: schema.TypeString,
+                            Optional: true,
+                            Default: cognitoidentityprovider.EmailSendingAccountTypeCognitoDefault,
+                            ValidateFunc: validation.StringInSlice([]string{
+                                cognitoidentityprovider.EmailSendingAccountTypeCognitoDefault,
+                                cognitoidentityprovider.EmailSendingAccountTypeDeveloper,
+                            }, false),
+                        },
+                        "from_email_address": {
+                            Type: schema.TypeString,
+                            Optional: true,
+                        },
+                        "reply_to_email_address": {
+                            Type: schema.TypeString,
+                            Optional: true,
+                        },
+                        "source_arn": {
+                            Type: schema.TypeString,
+                            Optional: true,
+                        },
+                    },
+                },
+            },
+            "sms_configuration": &schema.Schema{
+                Type: schema.TypeList,
+                Optional: true,
+                Computed: true,
+                MaxItems: 1,
+                Elem: &schema.Resource{
+                    Schema: map[string]*schema.Schema{
+                        // When set, the provider creates and owns an IAM role
+                        // that Cognito assumes to publish through SNS
+                        "create_role": {
+                            Type: schema.TypeBool,
+                            Optional: true,
+                            Default: false,
+                        },
+                        "external_id": {
+                            Type: schema.TypeString,
+                            Optional: true,
+                            Computed: true,
+                        },
+                        "role_name": {
+                            Type: schema.TypeString,
+                            Computed: true,
+                        },
+                        "sns_caller_arn": {
+                            Type: schema.TypeString,
+                            Optional: true,
+                            Computed: true,
+                        },
+                        "sns_region": {
+                            Type: schema.TypeString,
+                            Optional: true,
+                            Computed: true,
+                        },
+                    },
+                },
+            },
+            "verification_message_template": &schema.Schema{
+                Type: schema.TypeList,
+                Optional: true,
+                Computed: true,
+                MaxItems: 1,
+                Elem: &schema.Resource{
+                    Schema: map[string]*schema.Schema{
+                        "default_email_option": {
+                            Type: schema.TypeString,
+                            Optional: true,
+                            Default: cognitoidentityprovider.DefaultEmailOptionTypeConfirmWithCode,
+                            ValidateFunc: validation.StringInSlice([]string{
+                                cognitoidentityprovider.DefaultEmailOptionTypeConfirmWithCode,
+                                cognitoidentityprovider.DefaultEmailOptionTypeConfirmWithLink,
+                            }, false),
+                        },
+                        "email_message": {
+                            Type: schema.TypeString,
+                            Optional: true,
+                            Computed: true,
+                            ValidateFunc: validateCognitoIDPMessagePlaceholders("{####}"),
+                        },
+                        "email_message_by_link": {
+                            Type: schema.TypeString,
+                            Optional: true,
+                            Computed: true,
+                            ValidateFunc: validateCognitoIDPEmailMessageByLink,
+                        },
+                        "email_subject": {
+                            Type: schema.TypeString,
+                            Optional: true,
+                            Computed: true,
+                        },
+                        "email_subject_by_link": {
+                            Type: schema.TypeString,
+                            Optional: true,
+                            Computed: true,
+                        },
+                        "sms_message": {
+                            Type: schema.TypeString,
+                            Optional: true,
+                            Computed: true,
+                            ValidateFunc: validateCognitoIDPMessagePlaceholders("{####}"),
+                        },
+                    },
+                },
+            },
+            "admin_create_user_config": &schema.Schema{
+                Type: schema.TypeList,
+                Optional: true,
+                Computed: true,
+                MaxItems: 1,
+                Elem: &schema.Resource{
+                    Schema: map[string]*schema.Schema{
+                        "allow_admin_create_user_only": {
+                            Type: schema.TypeBool,
+                            Optional: true,
+                            Default: false,
+                        },
+                        "invite_message_template": {
+                            Type: schema.TypeList,
+                            Optional: true,
+                            MaxItems: 1,
+                            Elem: &schema.Resource{
+                                Schema: map[string]*schema.Schema{
+                                    "email_message": {
+                                        Type: schema.TypeString,
+                                        Optional: true,
+                                        ValidateFunc: validateCognitoIDPMessagePlaceholders("{username}", "{####}"),
+                                    },
+                                    "email_subject": {
+                                        Type: schema.TypeString,
+                                        Optional: true,
+                                    },
+                                    "sms_message": {
+                                        Type: schema.TypeString,
+                                        Optional: true,
+                                        ValidateFunc: validateCognitoIDPMessagePlaceholders("{username}", "{####}"),
+                                    },
+                                },
+                            },
+                        },
+                    },
+                },
+            },
+            "username_attributes": &schema.Schema{
+                Type: schema.TypeSet,
+                Optional: true,
+                ForceNew: true,
+                ConflictsWith: []string{"alias_attributes"},
+                Description: "Changing this replaces the pool and destroys every user in it",
+                Elem: &schema.Schema{
+                    Type: schema.TypeString,
+                    ValidateFunc: validation.StringInSlice([]string{
+                        cognitoidentityprovider.UsernameAttributeTypeEmail,
+                        cognitoidentityprovider.UsernameAttributeTypePhoneNumber,
+                    }, false),
+                },
+            },
+            "alias_attributes": &schema.Schema{
+                Type: schema.TypeSet,
+                Optional: true,
+                ForceNew: true,
+                ConflictsWith: []string{"username_attributes"},
+                Description: "Changing this replaces the pool and destroys every user in it",
+                Elem: &schema.Schema{
+                    Type: schema.TypeString,
+                    ValidateFunc: validation.StringInSlice([]string{
+                        cognitoidentityprovider.AliasAttributeTypeEmail,
+                        cognitoidentityprovider.AliasAttributeTypePhoneNumber,
+                        cognitoidentityprovider.AliasAttributeTypePreferredUsername,
+                    }, false),
+                },
+            },
+            "auto_verified_attributes": &schema.Schema{
+                Type: schema.TypeSet,
+                Optional: true,
+                Elem: &schema.Schema{
+                    Type: schema.TypeString,
+                    ValidateFunc: validation.StringInSlice([]string{
+                        cognitoidentityprovider.VerifiedAttributeTypeEmail,
+                        cognitoidentityprovider.VerifiedAttributeTypePhoneNumber,
+                    }, false),
+                },
+            },
+            "username_configuration": &schema.Schema{
+                Type: schema.TypeList,
+                Optional: true,
+                Computed: true,
+                ForceNew: true,
+                MaxItems: 1,
+                Elem: &schema.Resource{
+                    Schema: map[string]*schema.Schema{
+                        "case_sensitive": {
+                            Type: schema.TypeBool,
+                            Required: true,
+                            ForceNew: true,
+                            Description: "Changing this replaces the pool and destroys every user in it",
+                        },
+                    },
+                },
+            },
+            "user_attribute_update_settings": &schema.Schema{
+                Type: schema.TypeList,
+                Optional: true,
+                MaxItems: 1,
+                Elem: &schema.Resource{
+                    Schema: map[string]*schema.Schema{
+                        "attributes_require_verification_before_update": {
+                            Type: schema.TypeSet,
+                            Required: true,
+                            Elem: &schema.Schema{
+                                Type: schema.TypeString,
+                                ValidateFunc: validation.StringInSlice([]string{
+                                    cognitoidentityprovider.VerifiedAttributeTypeEmail,
+                                    cognitoidentityprovider.VerifiedAttributeTypePhoneNumber,
+                                }, false),
+                            },
+                        },
+                    },
+                },
+            },
+            "account_recovery_setting": &schema.Schema{
+                Type: schema.TypeList,
+                Optional: true,
+                Computed: true,
+                MaxItems: 1,
+                Elem: &schema.Resource{
+                    Schema: map[string]*schema.Schema{
+                        "recovery_mechanism": {
+                            Type: schema.TypeSet,
+                            Required: true,
+                            MinItems: 1,
+                            MaxItems: 2,
+                            Elem: &schema.Resource{
+                                Schema: map[string]*schema.Schema{
+                                    "name": {
+                                        Type: schema.TypeString,
+                                        Required: true,
+                                        ValidateFunc: validation.StringInSlice([]string{
+                                            cognitoidentityprovider.RecoveryOptionNameTypeVerifiedEmail,
+                                            cognitoidentityprovider.RecoveryOptionNameTypeVerifiedPhoneNumber,
+                                            cognitoidentityprovider.RecoveryOptionNameTypeAdminOnly,
+                                        }, false),
+                                    },
+                                    "priority": {
+                                        Type: schema.TypeInt,
+                                        Required: true,
+                                        ValidateFunc: validation.IntBetween(1, 2),
+                                    },
+                                },
+                            },
+                        },
+                    },
+                },
+            },
+            "device_configuration": &schema.Schema{
+                Type: schema.TypeList,
+                Optional: true,
+                MaxItems: 1,
+                Elem: &schema.Resource{
+                    Schema: map[string]*schema.Schema{
+                        "challenge_required_on_new_device": {
+                            Type: schema.TypeBool,
+                            Optional: true,
+                            Default: false,
+                        },
+                        "device_only_remembered_on_user_prompt": {
+                            Type: schema.TypeBool,
+                            Optional: true,
+                            Default: false,
+                        },
+                    },
+                },
+            },
+            "user_pool_add_ons": &schema.Schema{
+                Type: schema.TypeList,
+                Optional: true,
+                Computed: true,
+                MaxItems: 1,
+                Elem: &schema.Resource{
+                    Schema: map[string]*schema.Schema{
+                        "advanced_security_mode": {
+                            Type: schema.TypeString,
+                            Required: true,
+                            ValidateFunc: validation.StringInSlice([]string{
+                                cognitoidentityprovider.AdvancedSecurityModeTypeOff,
+                                cognitoidentityprovider.AdvancedSecurityModeTypeAudit,
+                                cognitoidentityprovider.AdvancedSecurityModeTypeEnforced,
+                            }, false),
+                        },
+                    },
+                },
+            },
+            "user_pool_tags": &schema.Schema{
+                Type: schema.TypeMap,
+                Optional: true,
+                Elem: &schema.Schema{Type: schema.TypeString},
+            },
+            "deletion_protection": &schema.Schema{
+                Type: schema.TypeString,
+                Optional: true,
+                Default: cognitoidentityprovider.DeletionProtectionTypeInactive,
+                ValidateFunc: validation.StringInSlice([]string{
+                    cognitoidentityprovider.DeletionProtectionTypeActive,
+                    cognitoidentityprovider.DeletionProtectionTypeInactive,
+                }, false),
+            },
+            "user_pool_tier": &schema.Schema{
+                Type: schema.TypeString,
+                Optional: true,
+                Computed: true,
+                ValidateFunc: validation.StringInSlice([]string{
+                    cognitoidentityprovider.UserPoolTierTypeLite,
+                    cognitoidentityprovider.UserPoolTierTypeEssentials,
+                    cognitoidentityprovider.UserPoolTierTypePlus,
+                }, false),
+            },
+            "force_destroy": &schema.Schema{
+                Type: schema.TypeBool,
+                Optional: true,
+                Default: false,
+            },
         },
     }
 }
 
 func resourceCognitoIDPUserPoolCreate(d *schema.ResourceData, meta interface{}) error {
     cidpconn := meta.(*AWSClient).cidpconn
+    iamconn := meta.(*AWSClient).iamconn
     poolname := d.Get("poolname").(string)
 
     params := &cognitoidentityprovider.CreateUserPoolInput{
@@ -78,21 +539,200 @@ func resourceCognitoIDPUserPoolCreate(d *schema.ResourceData, meta interface{})
         params.Policies = expandPolicies(d.Get("policies").(*schema.Set).List()[0].(map[string]interface{}))
     }
 
-    resp, err := cidpconn.CreateUserPool(params)
+    if v, ok := d.GetOk("lambda_config"); ok && v.([]interface{})[0] != nil {
+        params.LambdaConfig = expandLambdaConfig(v.([]interface{})[0].(map[string]interface{}))
+    }
+
+    if v, ok := d.GetOk("email_configuration"); ok && v.([]interface{})[0] != nil {
+        params.EmailConfiguration = expandEmailConfiguration(v.([]interface{})[0].(map[string]interface{}))
+    }
+
+    if v, ok := d.GetOk("verification_message_template"); ok && v.([]interface{})[0] != nil {
+        params.VerificationMessageTemplate = expandVerificationMessageTemplate(v.([]interface{})[0].(map[string]interface{}))
+    }
+
+    if v, ok := d.GetOk("admin_create_user_config"); ok && v.([]interface{})[0] != nil {
+        params.AdminCreateUserConfig = expandAdminCreateUserConfig(v.([]interface{})[0].(map[string]interface{}))
+    }
+
+    if v, ok := d.GetOk("username_attributes"); ok {
+        params.UsernameAttributes = expandStringSet(v.(*schema.Set))
+    }
+
+    if v, ok := d.GetOk("alias_attributes"); ok {
+        params.AliasAttributes = expandStringSet(v.(*schema.Set))
+    }
+
+    if v, ok := d.GetOk("auto_verified_attributes"); ok {
+        params.AutoVerifiedAttributes = expandStringSet(v.(*schema.Set))
+    }
+
+    if v, ok := d.GetOk("username_configuration"); ok && v.([]interface{})[0] != nil {
+        m := v.([]interface{})[0].(map[string]interface{})
+        params.UsernameConfiguration = &cognitoidentityprovider.UsernameConfigurationType{
+            CaseSensitive: aws.Bool(m["case_sensitive"].(bool)),
+        }
+    }
+
+    if v, ok := d.GetOk("user_attribute_update_settings"); ok && v.([]interface{})[0] != nil {
+        params.UserAttributeUpdateSettings = expandUserAttributeUpdateSettings(v.([]interface{})[0].(map[string]interface{}))
+    }
+
+    if v, ok := d.GetOk("account_recovery_setting"); ok && v.([]interface{})[0] != nil {
+        params.AccountRecoverySetting = expandAccountRecoverySetting(v.([]interface{})[0].(map[string]interface{}))
+    }
+
+    if v, ok := d.GetOk("device_configuration"); ok && v.([]interface{})[0] != nil {
+        params.DeviceConfiguration = expandDeviceConfiguration(v.([]interface{})[0].(map[string]interface{}))
+    }
+
+    if v, ok := d.GetOk("user_pool_add_ons"); ok && v.([]interface{})[0] != nil {
+        m := v.([]interface{})[0].(map[string]interface{})
+        params.UserPoolAddOns = &cognitoidentityprovider.UserPoolAddOnsType{
+            AdvancedSecurityMode: aws.String(m["advanced_security_mode"].(string)),
+        }
+    }
+
+    if v, ok := d.GetOk("user_pool_tags"); ok {
+        params.UserPoolTags = stringMapToPointers(v.(map[string]interface{}))
+    }
+
+    params.DeletionProtection = aws.String(d.Get("deletion_protection").(string))
+
+    if v, ok := d.GetOk("user_pool_tier"); ok {
+        params.UserPoolTier = aws.String(v.(string))
+    }
+
+    var smsRoleName string
+    if v, ok := d.GetOk("sms_configuration"); ok && v.([]interface{})[0] != nil {
+        m := v.([]interface{})[0].(map[string]interface{})
+        if m["create_role"].(bool) {
+            if err := createCognitoIDPSmsRole(iamconn, poolname, m); err != nil {
+                return err
+            }
+            smsRoleName = m["role_name"].(string)
+        }
+        params.SmsConfiguration = expandSmsConfiguration(m)
+        d.Set("sms_configuration", []interface{}{m})
+    }
+
+    var resp *cognitoidentityprovider.CreateUserPoolOutput
+    err := retryOnCognitoIDPSmsRolePropagation(func() error {
+        var err error
+        resp, err = cidpconn.CreateUserPool(params)
+        return err
+    })
     if err != nil {
+        if smsRoleName != "" {
+            if err := deleteCognitoIDPSmsRole(iamconn, smsRoleName); err != nil {
+                log.Printf("[WARN] %s", err)
+            }
+        }
         return fmt.Errorf("Error creating User Pool %s: %s", poolname, err)
     }
 
     d.SetId(*resp.UserPool.Id)
-    return nil
+    return resourceCognitoIDPUserPoolRead(d, meta)
 }
 
 func resourceCognitoIDPUserPoolRead(d *schema.ResourceData, meta interface{}) error {
+    cidpconn := meta.(*AWSClient).cidpconn
+    id := d.Id()
+
+    params := &cognitoidentityprovider.DescribeUserPoolInput{
+        UserPoolId: aws.String(id),
+    }
+
+    resp, err := cidpconn.DescribeUserPool(params)
+    if err != nil {
+        if isAWSErr(err, cognitoidentityprovider.ErrCodeResourceNotFoundException, "") {
+            log.Printf("[WARN] User Pool %s not found, removing from state", id)
+            d.SetId("")
+            return nil
+        }
+        return fmt.Errorf("Error reading User Pool %s: %s", id, err)
+    }
+
+    pool := resp.UserPool
+    d.Set("poolname", pool.Name)
+
+    return setCognitoIDPUserPoolConfiguration(d, pool)
+}
+
+// setCognitoIDPUserPoolConfiguration sets everything DescribeUserPool reports
+// back, and is shared with the trility_aws_cognito_user_pool data source
+func setCognitoIDPUserPoolConfiguration(d *schema.ResourceData, pool *cognitoidentityprovider.UserPoolType) error {
+    id := aws.StringValue(pool.Id)
+
+    if err := d.Set("lambda_config", flattenLambdaConfig(pool.LambdaConfig)); err != nil {
+        return fmt.Errorf("Error setting lambda_config for User Pool %s: %s", id, err)
+    }
+
+    if err := d.Set("email_configuration", flattenEmailConfiguration(pool.EmailConfiguration)); err != nil {
+        return fmt.Errorf("Error setting email_configuration for User Pool %s: %s", id, err)
+    }
+
+    if err := d.Set("sms_configuration", flattenSmsConfiguration(pool.SmsConfiguration, d)); err != nil {
+        return fmt.Errorf("Error setting sms_configuration for User Pool %s: %s", id, err)
+    }
+
+    if err := d.Set("verification_message_template", flattenVerificationMessageTemplate(pool.VerificationMessageTemplate)); err != nil {
+        return fmt.Errorf("Error setting verification_message_template for User Pool %s: %s", id, err)
+    }
+
+    if err := d.Set("admin_create_user_config", flattenAdminCreateUserConfig(pool.AdminCreateUserConfig)); err != nil {
+        return fmt.Errorf("Error setting admin_create_user_config for User Pool %s: %s", id, err)
+    }
+
+    if err := d.Set("username_attributes", flattenStringSet(pool.UsernameAttributes)); err != nil {
+        return fmt.Errorf("Error setting username_attributes for User Pool %s: %s", id, err)
+    }
+
+    if err := d.Set("alias_attributes", flattenStringSet(pool.AliasAttributes)); err != nil {
+        return fmt.Errorf("Error setting alias_attributes for User Pool %s: %s", id, err)
+    }
+
+    if err := d.Set("auto_verified_attributes", flattenStringSet(pool.AutoVerifiedAttributes)); err != nil {
+        return fmt.Errorf("Error setting auto_verified_attributes for User Pool %s: %s", id, err)
+    }
+
+    if c := pool.UsernameConfiguration; c != nil {
+        d.Set("username_configuration", []map[string]interface{}{
+            {"case_sensitive": aws.BoolValue(c.CaseSensitive)},
+        })
+    }
+
+    if err := d.Set("user_attribute_update_settings", flattenUserAttributeUpdateSettings(pool.UserAttributeUpdateSettings)); err != nil {
+        return fmt.Errorf("Error setting user_attribute_update_settings for User Pool %s: %s", id, err)
+    }
+
+    if err := d.Set("account_recovery_setting", flattenAccountRecoverySetting(pool.AccountRecoverySetting)); err != nil {
+        return fmt.Errorf("Error setting account_recovery_setting for User Pool %s: %s", id, err)
+    }
+
+    if err := d.Set("device_configuration", flattenDeviceConfiguration(pool.DeviceConfiguration)); err != nil {
+        return fmt.Errorf("Error setting device_configuration for User Pool %s: %s", id, err)
+    }
+
+    if a := pool.UserPoolAddOns; a != nil {
+        d.Set("user_pool_add_ons", []map[string]interface{}{
+            {"advanced_security_mode": aws.StringValue(a.AdvancedSecurityMode)},
+        })
+    }
+
+    if err := d.Set("user_pool_tags", pointersMapToStringList(pool.UserPoolTags)); err != nil {
+        return fmt.Errorf("Error setting user_pool_tags for User Pool %s: %s", id, err)
+    }
+
+    d.Set("deletion_protection", pool.DeletionProtection)
+    d.Set("user_pool_tier", pool.UserPoolTier)
+
     return nil
 }
 
 func resourceCognitoIDPUserPoolUpdate(d *schema.ResourceData, meta interface{}) error {
     cidpconn := meta.(*AWSClient).cidpconn
+    iamconn := meta.(*AWSClient).iamconn
     id := d.Id()
 
     params := &cognitoidentityprovider.UpdateUserPoolInput{
@@ -103,27 +743,242 @@ func resourceCognitoIDPUserPoolUpdate(d *schema.ResourceData, meta interface{})
         params.Policies = expandPolicies(d.Get("policies").(*schema.Set).List()[0].(map[string]interface{}))
     }
 
-    _, err := cidpconn.UpdateUserPool(params)
+    // UpdateUserPool resets anything left out of the request to its default,
+    // so every configured setting is sent, not just the ones that changed
+    if v, ok := d.GetOk("lambda_config"); ok && v.([]interface{})[0] != nil {
+        params.LambdaConfig = expandLambdaConfig(v.([]interface{})[0].(map[string]interface{}))
+    }
+
+    if v, ok := d.GetOk("email_configuration"); ok && v.([]interface{})[0] != nil {
+        params.EmailConfiguration = expandEmailConfiguration(v.([]interface{})[0].(map[string]interface{}))
+    }
+
+    if v, ok := d.GetOk("verification_message_template"); ok && v.([]interface{})[0] != nil {
+        params.VerificationMessageTemplate = expandVerificationMessageTemplate(v.([]interface{})[0].(map[string]interface{}))
+    }
+
+    if v, ok := d.GetOk("admin_create_user_config"); ok && v.([]interface{})[0] != nil {
+        params.AdminCreateUserConfig = expandAdminCreateUserConfig(v.([]interface{})[0].(map[string]interface{}))
+    }
+
+    if v, ok := d.GetOk("auto_verified_attributes"); ok {
+        params.AutoVerifiedAttributes = expandStringSet(v.(*schema.Set))
+    }
+
+    // Sent even without the block, so that removing it clears the
+    // requirement like every other setting left out of the request
+    if v, ok := d.GetOk("user_attribute_update_settings"); ok && v.([]interface{})[0] != nil {
+        params.UserAttributeUpdateSettings = expandUserAttributeUpdateSettings(v.([]interface{})[0].(map[string]interface{}))
+    } else {
+        params.UserAttributeUpdateSettings = &cognitoidentityprovider.UserAttributeUpdateSettingsType{
+            AttributesRequireVerificationBeforeUpdate: []*string{},
+        }
+    }
+
+    if v, ok := d.GetOk("account_recovery_setting"); ok && v.([]interface{})[0] != nil {
+        params.AccountRecoverySetting = expandAccountRecoverySetting(v.([]interface{})[0].(map[string]interface{}))
+    }
+
+    if v, ok := d.GetOk("device_configuration"); ok && v.([]interface{})[0] != nil {
+        params.DeviceConfiguration = expandDeviceConfiguration(v.([]interface{})[0].(map[string]interface{}))
+    }
+
+    if v, ok := d.GetOk("user_pool_add_ons"); ok && v.([]interface{})[0] != nil {
+        m := v.([]interface{})[0].(map[string]interface{})
+        params.UserPoolAddOns = &cognitoidentityprovider.UserPoolAddOnsType{
+            AdvancedSecurityMode: aws.String(m["advanced_security_mode"].(string)),
+        }
+    }
+
+    // An empty map is how tags are cleared
+    params.UserPoolTags = stringMapToPointers(d.Get("user_pool_tags").(map[string]interface{}))
+
+    params.DeletionProtection = aws.String(d.Get("deletion_protection").(string))
+
+    if v, ok := d.GetOk("user_pool_tier"); ok {
+        params.UserPoolTier = aws.String(v.(string))
+    }
+
+    o, _ := d.GetChange("sms_configuration")
+    var oldSmsRoleName string
+    if l := o.([]interface{}); len(l) > 0 && l[0] != nil {
+        oldSmsRoleName = l[0].(map[string]interface{})["role_name"].(string)
+    }
+
+    var smsRoleName string
+    if v, ok := d.GetOk("sms_configuration"); ok && v.([]interface{})[0] != nil {
+        m := v.([]interface{})[0].(map[string]interface{})
+        if m["create_role"].(bool) {
+            if oldSmsRoleName == "" {
+                if err := createCognitoIDPSmsRole(iamconn, d.Get("poolname").(string), m); err != nil {
+                    return err
+                }
+            } else {
+                m["role_name"] = oldSmsRoleName
+                if d.HasChange("sms_configuration.0.external_id") {
+                    if err := updateCognitoIDPSmsRoleExternalId(iamconn, m); err != nil {
+                        return err
+                    }
+                }
+            }
+            smsRoleName = m["role_name"].(string)
+        } else {
+            // role_name is carried over from state, and the role it names
+            // is removed below
+            m["role_name"] = ""
+        }
+        params.SmsConfiguration = expandSmsConfiguration(m)
+        d.Set("sms_configuration", []interface{}{m})
+    }
+
+    if oldSmsRoleName != "" && smsRoleName == "" && params.SmsConfiguration != nil {
+        oldArn := o.([]interface{})[0].(map[string]interface{})["sns_caller_arn"].(string)
+        if aws.StringValue(params.SmsConfiguration.SnsCallerArn) == oldArn {
+            return fmt.Errorf("User Pool %s would still send SMS through %s, the role removed along with create_role: set sms_configuration.0.sns_caller_arn to another role", id, oldArn)
+        }
+    }
+
+    err := retryOnCognitoIDPSmsRolePropagation(func() error {
+        _, err := cidpconn.UpdateUserPool(params)
+        return err
+    })
     if err != nil {
         return fmt.Errorf("Error updating User Pool %s: %s", id, err)
     }
 
-    return nil
+    // The pool no longer points at the role we created, so it can go
+    if oldSmsRoleName != "" && smsRoleName == "" {
+        if err := deleteCognitoIDPSmsRole(iamconn, oldSmsRoleName); err != nil {
+            return err
+        }
+    }
+
+    return resourceCognitoIDPUserPoolRead(d, meta)
 }
 
 func resourceCognitoIDPUserPoolDelete(d *schema.ResourceData, meta interface{}) error {
     cidpconn := meta.(*AWSClient).cidpconn
+    iamconn := meta.(*AWSClient).iamconn
     id := d.Id()
 
+    // Deleting a pool takes every account in it along, and replacements
+    // caused by ForceNew attributes end up here too
+    if !d.Get("force_destroy").(bool) {
+        users, err := cognitoIDPUserPoolEstimatedUsers(cidpconn, id)
+        if err != nil {
+            return err
+        }
+        if users > 0 {
+            return fmt.Errorf("User Pool %s still contains %d users who would be lost: set force_destroy to true and apply before destroying the pool", id, users)
+        }
+    }
+
     params := &cognitoidentityprovider.DeleteUserPoolInput{
         UserPoolId: aws.String(id),
     }
 
+    // A pool removed outside Terraform only leaves the SMS role to clean up
     _, err := cidpconn.DeleteUserPool(params)
-    if err != nil {
+    if err != nil && !isAWSErr(err, cognitoidentityprovider.ErrCodeResourceNotFoundException, "") {
+        if isAWSErr(err, cognitoidentityprovider.ErrCodeInvalidParameterException, "deletion protection") {
+            return fmt.Errorf("User Pool %s has deletion_protection set to %s: set it to %s and apply before destroying the pool",
+                id, cognitoidentityprovider.DeletionProtectionTypeActive, cognitoidentityprovider.DeletionProtectionTypeInactive)
+        }
         return fmt.Errorf("Error removing user pool id %s: %s", id, err)
     }
 
+    if v, ok := d.GetOk("sms_configuration.0.role_name"); ok {
+        if err := deleteCognitoIDPSmsRole(iamconn, v.(string)); err != nil {
+            return err
+        }
+    }
+
+    return nil
+}
+
+// cognitoIDPUserPoolEstimatedUsers returns 0 for a pool that no longer
+// exists, which is left for the apply to deal with
+func cognitoIDPUserPoolEstimatedUsers(cidpconn *cognitoidentityprovider.CognitoIdentityProvider, id string) (int64, error) {
+    resp, err := cidpconn.DescribeUserPool(&cognitoidentityprovider.DescribeUserPoolInput{
+        UserPoolId: aws.String(id),
+    })
+    if err != nil {
+        if isAWSErr(err, cognitoidentityprovider.ErrCodeResourceNotFoundException, "") {
+            return 0, nil
+        }
+        return 0, fmt.Errorf("Error reading User Pool %s: %s", id, err)
+    }
+
+    return aws.Int64Value(resp.UserPool.EstimatedNumberOfUsers), nil
+}
+
+// Settings Cognito only accepts in CreateUserPool
+var cognitoIDPUserPoolCreateOnlyKeys = []string{
+    "poolname",
+    "username_attributes",
+    "alias_attributes",
+    "username_configuration",
+}
+
+func resourceCognitoIDPUserPoolCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+    // Replacing a pool is not like replacing most resources: users cannot be
+    // moved between pools, so every account and password is lost. The plan
+    // fails here rather than the destroy halfway through the apply. Delete
+    // only sees force_destroy as it is in state, so that value counts here.
+    oldForceDestroy, _ := d.GetChange("force_destroy")
+    if d.Id() != "" && !oldForceDestroy.(bool) {
+        for _, k := range cognitoIDPUserPoolCreateOnlyKeys {
+            if !d.HasChange(k) {
+                continue
+            }
+
+            users, err := cognitoIDPUserPoolEstimatedUsers(meta.(*AWSClient).cidpconn, d.Id())
+            if err != nil {
+                return err
+            }
+            if users > 0 {
+                return fmt.Errorf("Changing %s replaces User Pool %s and destroys the %d users in it: set force_destroy to true and apply first", k, d.Id(), users)
+            }
+            break
+        }
+    }
+
+    // Cognito encrypts the codes it hands to custom senders, so a sender
+    // without a KMS key is rejected by the API
+    if v, ok := d.GetOk("lambda_config"); ok && v.([]interface{})[0] != nil {
+        m := v.([]interface{})[0].(map[string]interface{})
+        senders := len(m["custom_email_sender"].([]interface{})) > 0 || len(m["custom_sms_sender"].([]interface{})) > 0
+        if senders && m["kms_key_id"].(string) == "" && d.NewValueKnown("lambda_config.0.kms_key_id") {
+            return fmt.Errorf("lambda_config.0.kms_key_id is required when custom_email_sender or custom_sms_sender is set")
+        }
+    }
+
+    if v, ok := d.GetOk("email_configuration"); ok && v.([]interface{})[0] != nil {
+        m := v.([]interface{})[0].(map[string]interface{})
+        developer := m["email_sending_account"].(string) == cognitoidentityprovider.EmailSendingAccountTypeDeveloper
+        if developer && m["source_arn"].(string) == "" && d.NewValueKnown("email_configuration.0.source_arn") {
+            return fmt.Errorf("email_configuration.0.source_arn is required when email_sending_account is %s",
+                cognitoidentityprovider.EmailSendingAccountTypeDeveloper)
+        }
+    }
+
+    if v, ok := d.GetOk("sms_configuration"); ok && v.([]interface{})[0] != nil {
+        m := v.([]interface{})[0].(map[string]interface{})
+        if !m["create_role"].(bool) && m["sns_caller_arn"].(string) == "" && d.NewValueKnown("sms_configuration.0.sns_caller_arn") {
+            return fmt.Errorf("sms_configuration.0.sns_caller_arn is required unless create_role is set")
+        }
+
+        // sns_caller_arn keeps the managed role's ARN from state, which would
+        // leave the pool pointing at the role removed on apply
+        oc, _ := d.GetChange("sms_configuration.0.create_role")
+        if oc.(bool) && !m["create_role"].(bool) && d.NewValueKnown("sms_configuration.0.sns_caller_arn") {
+            o, n := d.GetChange("sms_configuration.0.sns_caller_arn")
+            if n.(string) == "" || n.(string) == o.(string) {
+                return fmt.Errorf("sms_configuration.0.sns_caller_arn must name a role other than %s, which is removed once create_role is unset", o.(string))
+            }
+        }
+    }
+
     return nil
 }
 
@@ -147,6 +1002,515 @@ func expandPasswordPolicy(m map[string]interface{}) *cognitoidentityprovider.Pas
     return ppt
 }
 
+func expandLambdaConfig(m map[string]interface{}) *cognitoidentityprovider.LambdaConfigType {
+    lct := &cognitoidentityprovider.LambdaConfigType{}
+
+    if v, ok := m["create_auth_challenge"].(string); ok && v != "" {
+        lct.CreateAuthChallenge = aws.String(v)
+    }
+    if v, ok := m["custom_email_sender"].([]interface{}); ok && len(v) > 0 && v[0] != nil {
+        c := v[0].(map[string]interface{})
+        lct.CustomEmailSender = &cognitoidentityprovider.CustomEmailLambdaVersionConfigType{
+            LambdaArn: aws.String(c["lambda_arn"].(string)),
+            LambdaVersion: aws.String(c["lambda_version"].(string)),
+        }
+    }
+    if v, ok := m["custom_message"].(string); ok && v != "" {
+        lct.CustomMessage = aws.String(v)
+    }
+    if v, ok := m["custom_sms_sender"].([]interface{}); ok && len(v) > 0 && v[0] != nil {
+        c := v[0].(map[string]interface{})
+        lct.CustomSMSSender = &cognitoidentityprovider.CustomSMSLambdaVersionConfigType{
+            LambdaArn: aws.String(c["lambda_arn"].(string)),
+            LambdaVersion: aws.String(c["lambda_version"].(string)),
+        }
+    }
+    if v, ok := m["define_auth_challenge"].(string); ok && v != "" {
+        lct.DefineAuthChallenge = aws.String(v)
+    }
+    if v, ok := m["kms_key_id"].(string); ok && v != "" {
+        lct.KMSKeyID = aws.String(v)
+    }
+    if v, ok := m["post_authentication"].(string); ok && v != "" {
+        lct.PostAuthentication = aws.String(v)
+    }
+    if v, ok := m["post_confirmation"].(string); ok && v != "" {
+        lct.PostConfirmation = aws.String(v)
+    }
+    if v, ok := m["pre_authentication"].(string); ok && v != "" {
+        lct.PreAuthentication = aws.String(v)
+    }
+    if v, ok := m["pre_sign_up"].(string); ok && v != "" {
+        lct.PreSignUp = aws.String(v)
+    }
+    // The versioned config takes over PreTokenGeneration, so sending both
+    // would keep a removed trigger attached
+    if v, ok := m["pre_token_generation_config"].([]interface{}); ok && len(v) > 0 && v[0] != nil {
+        c := v[0].(map[string]interface{})
+        lct.PreTokenGenerationConfig = &cognitoidentityprovider.PreTokenGenerationVersionConfigType{
+            LambdaArn: aws.String(c["lambda_arn"].(string)),
+            LambdaVersion: aws.String(c["lambda_version"].(string)),
+        }
+    } else if v, ok := m["pre_token_generation"].(string); ok && v != "" {
+        lct.PreTokenGeneration = aws.String(v)
+    }
+    if v, ok := m["user_migration"].(string); ok && v != "" {
+        lct.UserMigration = aws.String(v)
+    }
+    if v, ok := m["verify_auth_challenge_response"].(string); ok && v != "" {
+        lct.VerifyAuthChallengeResponse = aws.String(v)
+    }
+
+    return lct
+}
+
+func flattenLambdaConfig(lct *cognitoidentityprovider.LambdaConfigType) []map[string]interface{} {
+    // DescribeUserPool returns an empty LambdaConfig rather than nil when no
+    // triggers are attached
+    if lct == nil || *lct == (cognitoidentityprovider.LambdaConfigType{}) {
+        return nil
+    }
+
+    m := map[string]interface{}{
+        "create_auth_challenge": aws.StringValue(lct.CreateAuthChallenge),
+        "custom_message": aws.StringValue(lct.CustomMessage),
+        "define_auth_challenge": aws.StringValue(lct.DefineAuthChallenge),
+        "kms_key_id": aws.StringValue(lct.KMSKeyID),
+        "post_authentication": aws.StringValue(lct.PostAuthentication),
+        "post_confirmation": aws.StringValue(lct.PostConfirmation),
+        "pre_authentication": aws.StringValue(lct.PreAuthentication),
+        "pre_sign_up": aws.StringValue(lct.PreSignUp),
+        "pre_token_generation": aws.StringValue(lct.PreTokenGeneration),
+        "user_migration": aws.StringValue(lct.UserMigration),
+        "verify_auth_challenge_response": aws.StringValue(lct.VerifyAuthChallengeResponse),
+    }
+
+    if c := lct.CustomEmailSender; c != nil {
+        m["custom_email_sender"] = []map[string]interface{}{
+            {
+                "lambda_arn": aws.StringValue(c.LambdaArn),
+                "lambda_version": aws.StringValue(c.LambdaVersion),
+            },
+        }
+    }
+
+    if c := lct.CustomSMSSender; c != nil {
+        m["custom_sms_sender"] = []map[string]interface{}{
+            {
+                "lambda_arn": aws.StringValue(c.LambdaArn),
+                "lambda_version": aws.StringValue(c.LambdaVersion),
+            },
+        }
+    }
+
+    if c := lct.PreTokenGenerationConfig; c != nil {
+        if aws.StringValue(lct.PreTokenGeneration) == aws.StringValue(c.LambdaArn) {
+            m["pre_token_generation"] = ""
+        }
+        m["pre_token_generation_config"] = []map[string]interface{}{
+            {
+                "lambda_arn": aws.StringValue(c.LambdaArn),
+                "lambda_version": aws.StringValue(c.LambdaVersion),
+            },
+        }
+    }
+
+    return []map[string]interface{}{m}
+}
+
+func expandEmailConfiguration(m map[string]interface{}) *cognitoidentityprovider.EmailConfigurationType {
+    ect := &cognitoidentityprovider.EmailConfigurationType{
+        EmailSendingAccount: aws.String(m["email_sending_account"].(string)),
+    }
+
+    if v, ok := m["configuration_set"].(string); ok && v != "" {
+        ect.ConfigurationSet = aws.String(v)
+    }
+    if v, ok := m["from_email_address"].(string); ok && v != "" {
+        ect.From = aws.String(v)
+    }
+    if v, ok := m["reply_to_email_address"].(string); ok && v != "" {
+        ect.ReplyToEmailAddress = aws.String(v)
+    }
+    if v, ok := m["source_arn"].(string); ok && v != "" {
+        ect.SourceArn = aws.String(v)
+    }
+
+    return ect
+}
+
+func flattenEmailConfiguration(ect *cognitoidentityprovider.EmailConfigurationType) []map[string]interface{} {
+    if ect == nil {
+        return nil
+    }
+
+    m := map[string]interface{}{
+        "configuration_set": aws.StringValue(ect.ConfigurationSet),
+        "email_sending_account": aws.StringValue(ect.EmailSendingAccount),
+        "from_email_address": aws.StringValue(ect.From),
+        "reply_to_email_address": aws.StringValue(ect.ReplyToEmailAddress),
+        "source_arn": aws.StringValue(ect.SourceArn),
+    }
+
+    return []map[string]interface{}{m}
+}
+
+func expandSmsConfiguration(m map[string]interface{}) *cognitoidentityprovider.SmsConfigurationType {
+    sct := &cognitoidentityprovider.SmsConfigurationType{
+        SnsCallerArn: aws.String(m["sns_caller_arn"].(string)),
+    }
+
+    if v, ok := m["external_id"].(string); ok && v != "" {
+        sct.ExternalId = aws.String(v)
+    }
+    if v, ok := m["sns_region"].(string); ok && v != "" {
+        sct.SnsRegion = aws.String(v)
+    }
+
+    return sct
+}
+
+// create_role and role_name only exist on our side, so they are carried
+// over from the current state rather than read from the API
+func flattenSmsConfiguration(sct *cognitoidentityprovider.SmsConfigurationType, d *schema.ResourceData) []map[string]interface{} {
+    if sct == nil {
+        return nil
+    }
+
+    m := map[string]interface{}{
+        "create_role": d.Get("sms_configuration.0.create_role").(bool),
+        "external_id": aws.StringValue(sct.ExternalId),
+        "role_name": d.Get("sms_configuration.0.role_name").(string),
+        "sns_caller_arn": aws.StringValue(sct.SnsCallerArn),
+        "sns_region": aws.StringValue(sct.SnsRegion),
+    }
+
+    return []map[string]interface{}{m}
+}
+
+func cognitoIDPSmsRoleTrustPolicy(externalId string) (string, error) {
+    policy := map[string]interface{}{
+        "Version": "2012-10-17",
+        "Statement": []map[string]interface{}{
+            {
+                "Effect": "Allow",
+                "Principal": map[string]string{
+                    "Service": "cognito-idp.amazonaws.com",
+                },
+                "Action": "sts:AssumeRole",
+                "Condition": map[string]interface{}{
+                    "StringEquals": map[string]string{
+                        "sts:ExternalId": externalId,
+                    },
+                },
+            },
+        },
+    }
+
+    b, err := json.Marshal(policy)
+    return string(b), err
+}
+
+const cognitoIDPSmsRolePolicy = `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Action": "sns:Publish",
+      "Resource": "*"
+    }
+  ]
+}`
+
+// createCognitoIDPSmsRole creates the IAM role Cognito assumes to send SMS
+// and fills role_name, sns_caller_arn and, when unset, external_id into m
+func createCognitoIDPSmsRole(iamconn *iam.IAM, poolname string, m map[string]interface{}) error {
+    externalId := m["external_id"].(string)
+    if externalId == "" {
+        externalId = resource.UniqueId()
+    }
+
+    trust, err := cognitoIDPSmsRoleTrustPolicy(externalId)
+    if err != nil {
+        return err
+    }
+
+    roleName := resource.PrefixedUniqueId("cognito-sms-")
+    log.Printf("[DEBUG] Creating SMS role %s for User Pool %s", roleName, poolname)
+    out, err := iamconn.CreateRole(&iam.CreateRoleInput{
+        RoleName: aws.String(roleName),
+        AssumeRolePolicyDocument: aws.String(trust),
+        Description: aws.String(fmt.Sprintf("Allows Cognito User Pool %s to send SMS", poolname)),
+    })
+    if err != nil {
+        return fmt.Errorf("Error creating SMS role for User Pool %s: %s", poolname, err)
+    }
+
+    _, err = iamconn.PutRolePolicy(&iam.PutRolePolicyInput{
+        RoleName: aws.String(roleName),
+        PolicyName: aws.String("sns-publish"),
+        PolicyDocument: aws.String(cognitoIDPSmsRolePolicy),
+    })
+    if err != nil {
+        if derr := deleteCognitoIDPSmsRole(iamconn, roleName); derr != nil {
+            log.Printf("[WARN] %s", derr)
+        }
+        return fmt.Errorf("Error attaching policy to SMS role %s: %s", roleName, err)
+    }
+
+    err = iamconn.WaitUntilRoleExists(&iam.GetRoleInput{
+        RoleName: aws.String(roleName),
+    })
+    if err != nil {
+        if derr := deleteCognitoIDPSmsRole(iamconn, roleName); derr != nil {
+            log.Printf("[WARN] %s", derr)
+        }
+        return fmt.Errorf("Error waiting for SMS role %s: %s", roleName, err)
+    }
+
+    m["external_id"] = externalId
+    m["role_name"] = roleName
+    m["sns_caller_arn"] = *out.Role.Arn
+    return nil
+}
+
+func updateCognitoIDPSmsRoleExternalId(iamconn *iam.IAM, m map[string]interface{}) error {
+    roleName := m["role_name"].(string)
+
+    trust, err := cognitoIDPSmsRoleTrustPolicy(m["external_id"].(string))
+    if err != nil {
+        return err
+    }
+
+    _, err = iamconn.UpdateAssumeRolePolicy(&iam.UpdateAssumeRolePolicyInput{
+        RoleName: aws.String(roleName),
+        PolicyDocument: aws.String(trust),
+    })
+    if err != nil {
+        return fmt.Errorf("Error updating trust policy of SMS role %s: %s", roleName, err)
+    }
+
+    return nil
+}
+
+func deleteCognitoIDPSmsRole(iamconn *iam.IAM, roleName string) error {
+    _, err := iamconn.DeleteRolePolicy(&iam.DeleteRolePolicyInput{
+        RoleName: aws.String(roleName),
+        PolicyName: aws.String("sns-publish"),
+    })
+    if err != nil && !isAWSErr(err, iam.ErrCodeNoSuchEntityException, "") {
+        return fmt.Errorf("Error removing policy from SMS role %s: %s", roleName, err)
+    }
+
+    _, err = iamconn.DeleteRole(&iam.DeleteRoleInput{
+        RoleName: aws.String(roleName),
+    })
+    if err != nil && !isAWSErr(err, iam.ErrCodeNoSuchEntityException, "") {
+        return fmt.Errorf("Error removing SMS role %s: %s", roleName, err)
+    }
+
+    return nil
+}
+
+// IAM is eventually consistent, so Cognito can reject a role that was only
+// just created until the trust and access policies have propagated
+func retryOnCognitoIDPSmsRolePropagation(f func() error) error {
+    return resource.Retry(2*time.Minute, func() *resource.RetryError {
+        err := f()
+        if isAWSErr(err, cognitoidentityprovider.ErrCodeInvalidSmsRoleTrustRelationshipException, "") ||
+            isAWSErr(err, cognitoidentityprovider.ErrCodeInvalidSmsRoleAccessPolicyException, "") {
+            return resource.RetryableError(err)
+        }
+        if err != nil {
+            return resource.NonRetryableError(err)
+        }
+        return nil
+    })
+}
+
+func expandVerificationMessageTemplate(m map[string]interface{}) *cognitoidentityprovider.VerificationMessageTemplateType {
+    vmt := &cognitoidentityprovider.VerificationMessageTemplateType{
+        DefaultEmailOption: aws.String(m["default_email_option"].(string)),
+    }
+
+    if v, ok := m["email_message"].(string); ok && v != "" {
+        vmt.EmailMessage = aws.String(v)
+    }
+    if v, ok := m["email_message_by_link"].(string); ok && v != "" {
+        vmt.EmailMessageByLink = aws.String(v)
+    }
+    if v, ok := m["email_subject"].(string); ok && v != "" {
+        vmt.EmailSubject = aws.String(v)
+    }
+    if v, ok := m["email_subject_by_link"].(string); ok && v != "" {
+        vmt.EmailSubjectByLink = aws.String(v)
+    }
+    if v, ok := m["sms_message"].(string); ok && v != "" {
+        vmt.SmsMessage = aws.String(v)
+    }
+
+    return vmt
+}
+
+func flattenVerificationMessageTemplate(vmt *cognitoidentityprovider.VerificationMessageTemplateType) []map[string]interface{} {
+    if vmt == nil {
+        return nil
+    }
+
+    m := map[string]interface{}{
+        "default_email_option": aws.StringValue(vmt.DefaultEmailOption),
+        "email_message": aws.StringValue(vmt.EmailMessage),
+        "email_message_by_link": aws.StringValue(vmt.EmailMessageByLink),
+        "email_subject": aws.StringValue(vmt.EmailSubject),
+        "email_subject_by_link": aws.StringValue(vmt.EmailSubjectByLink),
+        "sms_message": aws.StringValue(vmt.SmsMessage),
+    }
+
+    return []map[string]interface{}{m}
+}
+
+func expandAdminCreateUserConfig(m map[string]interface{}) *cognitoidentityprovider.AdminCreateUserConfigType {
+    acuc := &cognitoidentityprovider.AdminCreateUserConfigType{
+        AllowAdminCreateUserOnly: aws.Bool(m["allow_admin_create_user_only"].(bool)),
+    }
+
+    if v, ok := m["invite_message_template"].([]interface{}); ok && len(v) > 0 && v[0] != nil {
+        t := v[0].(map[string]interface{})
+        mt := &cognitoidentityprovider.MessageTemplateType{}
+
+        if v, ok := t["email_message"].(string); ok && v != "" {
+            mt.EmailMessage = aws.String(v)
+        }
+        if v, ok := t["email_subject"].(string); ok && v != "" {
+            mt.EmailSubject = aws.String(v)
+        }
+        if v, ok := t["sms_message"].(string); ok && v != "" {
+            mt.SMSMessage = aws.String(v)
+        }
+
+        acuc.InviteMessageTemplate = mt
+    }
+
+    return acuc
+}
+
+func flattenAdminCreateUserConfig(acuc *cognitoidentityprovider.AdminCreateUserConfigType) []map[string]interface{} {
+    if acuc == nil {
+        return nil
+    }
+
+    m := map[string]interface{}{
+        "allow_admin_create_user_only": aws.BoolValue(acuc.AllowAdminCreateUserOnly),
+    }
+
+    if mt := acuc.InviteMessageTemplate; mt != nil && *mt != (cognitoidentityprovider.MessageTemplateType{}) {
+        m["invite_message_template"] = []map[string]interface{}{
+            {
+                "email_message": aws.StringValue(mt.EmailMessage),
+                "email_subject": aws.StringValue(mt.EmailSubject),
+                "sms_message": aws.StringValue(mt.SMSMessage),
+            },
+        }
+    }
+
+    return []map[string]interface{}{m}
+}
+
+// validateCognitoIDPMessagePlaceholders checks that a message template
+// carries every placeholder Cognito substitutes into it
+func validateCognitoIDPMessagePlaceholders(placeholders ...string) schema.SchemaValidateFunc {
+    return func(v interface{}, k string) (ws []string, errors []error) {
+        value := v.(string)
+        for _, p := range placeholders {
+            if !strings.Contains(value, p) {
+                errors = append(errors, fmt.Errorf("%q must contain the %s placeholder", k, p))
+            }
+        }
+        return
+    }
+}
+
+func validateCognitoIDPEmailMessageByLink(v interface{}, k string) (ws []string, errors []error) {
+    value := v.(string)
+    if !regexp.MustCompile(`\{##[\S\s]*##\}`).MatchString(value) {
+        errors = append(errors, fmt.Errorf("%q must contain the {##Click Here##} link placeholder", k))
+    }
+    return
+}
+
+func expandUserAttributeUpdateSettings(m map[string]interface{}) *cognitoidentityprovider.UserAttributeUpdateSettingsType {
+    return &cognitoidentityprovider.UserAttributeUpdateSettingsType{
+        AttributesRequireVerificationBeforeUpdate: expandStringSet(m["attributes_require_verification_before_update"].(*schema.Set)),
+    }
+}
+
+func flattenUserAttributeUpdateSettings(uaus *cognitoidentityprovider.UserAttributeUpdateSettingsType) []map[string]interface{} {
+    if uaus == nil || len(uaus.AttributesRequireVerificationBeforeUpdate) == 0 {
+        return nil
+    }
+
+    m := map[string]interface{}{
+        "attributes_require_verification_before_update": flattenStringSet(uaus.AttributesRequireVerificationBeforeUpdate),
+    }
+
+    return []map[string]interface{}{m}
+}
+
+func expandAccountRecoverySetting(m map[string]interface{}) *cognitoidentityprovider.AccountRecoverySettingType {
+    mechanisms := make([]*cognitoidentityprovider.RecoveryOptionType, 0)
+    for _, raw := range m["recovery_mechanism"].(*schema.Set).List() {
+        rm := raw.(map[string]interface{})
+        mechanisms = append(mechanisms, &cognitoidentityprovider.RecoveryOptionType{
+            Name: aws.String(rm["name"].(string)),
+            Priority: aws.Int64(int64(rm["priority"].(int))),
+        })
+    }
+
+    return &cognitoidentityprovider.AccountRecoverySettingType{
+        RecoveryMechanisms: mechanisms,
+    }
+}
+
+func flattenAccountRecoverySetting(ars *cognitoidentityprovider.AccountRecoverySettingType) []map[string]interface{} {
+    if ars == nil || len(ars.RecoveryMechanisms) == 0 {
+        return nil
+    }
+
+    mechanisms := make([]interface{}, 0, len(ars.RecoveryMechanisms))
+    for _, rm := range ars.RecoveryMechanisms {
+        mechanisms = append(mechanisms, map[string]interface{}{
+            "name": aws.StringValue(rm.Name),
+            "priority": int(aws.Int64Value(rm.Priority)),
+        })
+    }
+
+    m := map[string]interface{}{
+        "recovery_mechanism": mechanisms,
+    }
+
+    return []map[string]interface{}{m}
+}
+
+func expandDeviceConfiguration(m map[string]interface{}) *cognitoidentityprovider.DeviceConfigurationType {
+    return &cognitoidentityprovider.DeviceConfigurationType{
+        ChallengeRequiredOnNewDevice: aws.Bool(m["challenge_required_on_new_device"].(bool)),
+        DeviceOnlyRememberedOnUserPrompt: aws.Bool(m["device_only_remembered_on_user_prompt"].(bool)),
+    }
+}
+
+func flattenDeviceConfiguration(dc *cognitoidentityprovider.DeviceConfigurationType) []map[string]interface{} {
+    if dc == nil {
+        return nil
+    }
+
+    m := map[string]interface{}{
+        "challenge_required_on_new_device": aws.BoolValue(dc.ChallengeRequiredOnNewDevice),
+        "device_only_remembered_on_user_prompt": aws.BoolValue(dc.DeviceOnlyRememberedOnUserPrompt),
+    }
+
+    return []map[string]interface{}{m}
+}
+
 // TypeSet Attribute
 func policiesHash(v interface{}) int {
     var buf bytes.Buffer